@@ -0,0 +1,235 @@
+package zabbix
+
+import (
+	"encoding/json"
+)
+
+type (
+	OperationType int
+)
+
+const (
+	SendMessageOperation       OperationType = 0
+	RemoteCommandOperation     OperationType = 1
+	NotifyAllInvolvedOperation OperationType = 11
+)
+
+// https://www.zabbix.com/documentation/3.2/manual/api/reference/action/object#action_operation_message
+type OpMessage struct {
+	DefaultMsg  int    `json:"default_msg,string"`
+	Subject     string `json:"subject,omitempty"`
+	Message     string `json:"message,omitempty"`
+	MediaTypeId string `json:"mediatypeid,omitempty"`
+}
+
+type OpMessageGroup struct {
+	UserGroupId string `json:"usrgrpid"`
+}
+
+type OpMessageUser struct {
+	UserId string `json:"userid"`
+}
+
+// https://www.zabbix.com/documentation/3.2/manual/api/reference/action/object#action_operation
+type ActionOperation struct {
+	OperationId   string        `json:"operationid,omitempty"`
+	OperationType OperationType `json:"operationtype,string"`
+
+	// Escalation steps, EscStepTo 0 means until problem is resolved, so it's always sent.
+	EscStepFrom int    `json:"esc_step_from,string"`
+	EscStepTo   int    `json:"esc_step_to,string"`
+	EscPeriod   string `json:"esc_period,omitempty"` // "0" means default action period
+
+	OpMessage       *OpMessage       `json:"opmessage,omitempty"`
+	OpMessageGroups []OpMessageGroup `json:"opmessage_grp,omitempty"`
+	OpMessageUsers  []OpMessageUser  `json:"opmessage_usr,omitempty"`
+}
+
+type ActionOperations []ActionOperation
+
+// https://www.zabbix.com/documentation/3.2/manual/api/reference/action/object#action_recovery_operation
+type ActionRecoveryOperation struct {
+	OperationId   string        `json:"operationid,omitempty"`
+	OperationType OperationType `json:"operationtype,string"`
+
+	OpMessage       *OpMessage       `json:"opmessage,omitempty"`
+	OpMessageGroups []OpMessageGroup `json:"opmessage_grp,omitempty"`
+	OpMessageUsers  []OpMessageUser  `json:"opmessage_usr,omitempty"`
+}
+
+type ActionRecoveryOperations []ActionRecoveryOperation
+
+// https://www.zabbix.com/documentation/3.2/manual/api/reference/action/object
+type Action struct {
+	ActionId    string      `json:"actionid,omitempty"`
+	Name        string      `json:"name"`
+	EventSource EventSource `json:"eventsource,string"`
+	Status      Status      `json:"status,string"`
+	EscPeriod   string      `json:"esc_period,omitempty"`
+
+	// Pause escalation while problem is suppressed by maintenance. Zabbix default is used if nil.
+	// Sent as pause_suppressed since Zabbix 4.0 and as maintenance_mode before it.
+	PauseSuppressed *bool `json:"-"`
+
+	// Returned by selectOperations and selectRecoveryOperations query parameters.
+	Operations         ActionOperations         `json:"operations,omitempty"`
+	RecoveryOperations ActionRecoveryOperations `json:"recovery_operations,omitempty"`
+}
+
+// Accepts recoveryOperations returned by action.get as well as recovery_operations used on create.
+func (action *Action) UnmarshalJSON(b []byte) (err error) {
+	type plain Action
+	var a struct {
+		plain
+		RecoveryOperationsGet ActionRecoveryOperations `json:"recoveryOperations"`
+		PauseSuppressed       string                   `json:"pause_suppressed"`
+		MaintenanceMode       string                   `json:"maintenance_mode"`
+	}
+	err = json.Unmarshal(b, &a)
+	if err != nil {
+		return
+	}
+
+	*action = Action(a.plain)
+	if action.RecoveryOperations == nil {
+		action.RecoveryOperations = a.RecoveryOperationsGet
+	}
+	for _, s := range []string{a.PauseSuppressed, a.MaintenanceMode} {
+		if s != "" {
+			pause := s != "0"
+			action.PauseSuppressed = &pause
+		}
+	}
+	return
+}
+
+// Sends PauseSuppressed as pause_suppressed.
+func (action Action) MarshalJSON() ([]byte, error) {
+	return action.marshal(false)
+}
+
+// Sends PauseSuppressed as maintenance_mode for Zabbix before 4.0.
+type legacyAction Action
+
+func (action legacyAction) MarshalJSON() ([]byte, error) {
+	return Action(action).marshal(true)
+}
+
+func (action Action) marshal(legacy bool) ([]byte, error) {
+	type plain Action
+	a := struct {
+		plain
+		PauseSuppressed *string `json:"pause_suppressed,omitempty"`
+		MaintenanceMode *string `json:"maintenance_mode,omitempty"`
+	}{plain: plain(action)}
+
+	if action.PauseSuppressed != nil {
+		pause := "0"
+		if *action.PauseSuppressed {
+			pause = "1"
+		}
+		if legacy {
+			a.MaintenanceMode = &pause
+		} else {
+			a.PauseSuppressed = &pause
+		}
+	}
+	return json.Marshal(a)
+}
+
+type Actions []Action
+
+func (actions Actions) pauseSuppressedSet() bool {
+	for _, action := range actions {
+		if action.PauseSuppressed != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Wrapper for action.get: https://www.zabbix.com/documentation/3.2/manual/api/reference/action/get
+func (api *API) ActionsGet(params Params) (res Actions, err error) {
+	params = params.Clone()
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	err = api.callInto("action.get", params, &res)
+	return
+}
+
+// Wrapper for action.create: https://www.zabbix.com/documentation/3.2/manual/api/reference/action/create
+func (api *API) ActionsCreate(actions Actions) (err error) {
+	var payload interface{} = actions
+	if actions.pauseSuppressedSet() {
+		var v version
+		v, err = api.serverVersion()
+		if err != nil {
+			return
+		}
+		if !v.atLeast(4, 0) {
+			legacy := make([]legacyAction, len(actions))
+			for i := range actions {
+				legacy[i] = legacyAction(actions[i])
+			}
+			payload = legacy
+		}
+	}
+
+	response, err := api.CallWithError("action.create", payload)
+	if err != nil {
+		return
+	}
+
+	actionids, err := response.ResultIDs("actionids")
+	if err != nil {
+		return
+	}
+	for i, id := range actionids {
+		actions[i].ActionId = id
+	}
+	return
+}
+
+// Creates actions which pause escalations while problems are suppressed by maintenance.
+func (api *API) ActionsCreatePaused(actions Actions) (err error) {
+	pause := true
+	for i := range actions {
+		actions[i].PauseSuppressed = &pause
+	}
+	return api.ActionsCreate(actions)
+}
+
+// Wrapper for action.delete: https://www.zabbix.com/documentation/3.2/manual/api/reference/action/delete
+// Cleans ActionId in all actions elements if call succeed.
+func (api *API) ActionsDelete(actions Actions) (err error) {
+	ids := make([]string, len(actions))
+	for i, action := range actions {
+		ids[i] = action.ActionId
+	}
+
+	err = api.ActionsDeleteByIds(ids)
+	if err == nil {
+		for i := range actions {
+			actions[i].ActionId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for action.delete: https://www.zabbix.com/documentation/3.2/manual/api/reference/action/delete
+func (api *API) ActionsDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("action.delete", ids)
+	if err != nil {
+		return
+	}
+
+	actionids, err := response.ResultIDs("actionids")
+	if err != nil {
+		return
+	}
+	if len(ids) != len(actionids) {
+		err = &ExpectedMore{len(ids), len(actionids)}
+	}
+	return
+}
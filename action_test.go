@@ -0,0 +1,122 @@
+package zabbix_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	. "."
+)
+
+func TestActionsCreateEscalation(t *testing.T) {
+	var created []map[string]interface{}
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		if call.Method != "action.create" {
+			t.Errorf("Unexpected method %s", call.Method)
+		}
+		call.decodeParams(&created, t)
+		return map[string]interface{}{"actionids": []string{"7"}}
+	})
+	defer server.Close()
+
+	actions := Actions{{
+		Name:        "Page team, then manager",
+		EventSource: TriggerEventSource,
+		EscPeriod:   "15m",
+		Operations: ActionOperations{
+			{
+				OperationType:   SendMessageOperation,
+				EscStepFrom:     1,
+				EscStepTo:       2,
+				OpMessage:       &OpMessage{DefaultMsg: 1},
+				OpMessageGroups: []OpMessageGroup{{"13"}},
+			},
+			{
+				OperationType:  SendMessageOperation,
+				EscStepFrom:    3,
+				EscStepTo:      0,
+				EscPeriod:      "1h",
+				OpMessage:      &OpMessage{DefaultMsg: 1},
+				OpMessageUsers: []OpMessageUser{{"5"}},
+			},
+		},
+		RecoveryOperations: ActionRecoveryOperations{{OperationType: NotifyAllInvolvedOperation, OpMessage: &OpMessage{DefaultMsg: 1}}},
+	}}
+	err := api.ActionsCreate(actions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actions[0].ActionId != "7" {
+		t.Errorf("Id is not set: %#v", actions[0])
+	}
+
+	expected := []interface{}{
+		map[string]interface{}{
+			"operationtype": "0", "esc_step_from": "1", "esc_step_to": "2",
+			"opmessage":     map[string]interface{}{"default_msg": "1"},
+			"opmessage_grp": []interface{}{map[string]interface{}{"usrgrpid": "13"}},
+		},
+		map[string]interface{}{
+			"operationtype": "0", "esc_step_from": "3", "esc_step_to": "0", "esc_period": "1h",
+			"opmessage":     map[string]interface{}{"default_msg": "1"},
+			"opmessage_usr": []interface{}{map[string]interface{}{"userid": "5"}},
+		},
+	}
+	if len(created) != 1 || !reflect.DeepEqual(created[0]["operations"], expected) {
+		t.Errorf("Unexpected operations:\n%#v\n%#v", created, expected)
+	}
+	if created[0]["recovery_operations"] == nil {
+		t.Errorf("Recovery operations are not sent: %#v", created)
+	}
+}
+
+func TestActionUnmarshalRecoveryOperations(t *testing.T) {
+	var action Action
+	err := json.Unmarshal([]byte(`{"actionid": "7", "name": "a", "eventsource": "0", "status": "0",
+		"recoveryOperations": [{"operationid": "9", "operationtype": "11"}]}`), &action)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(action.RecoveryOperations) != 1 || action.RecoveryOperations[0].OperationType != NotifyAllInvolvedOperation {
+		t.Errorf("Bad action: %#v", action)
+	}
+}
+
+func TestActionsCreatePaused(t *testing.T) {
+	for version, key := range map[string]string{"3.4.15": "maintenance_mode", "4.0.0": "pause_suppressed", "5.0.1": "pause_suppressed"} {
+		var created []map[string]interface{}
+		api, server := newMockAPI(t, func(call *mockCall) interface{} {
+			switch call.Method {
+			case "apiinfo.version", "APIInfo.version":
+				return version
+			case "action.create":
+				call.decodeParams(&created, t)
+				return map[string]interface{}{"actionids": []string{"7"}}
+			}
+			t.Errorf("%s: unexpected method %s", version, call.Method)
+			return nil
+		})
+
+		actions := Actions{{Name: "Notify admins", EventSource: TriggerEventSource, EscPeriod: "1h"}}
+		err := api.ActionsCreatePaused(actions)
+		server.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(created) != 1 || created[0][key] != "1" || created[0]["name"] != "Notify admins" || created[0]["esc_period"] != "1h" {
+			t.Errorf("%s: unexpected payload: %#v", version, created)
+		}
+		if len(created) == 1 && len(created[0]) != 5 {
+			t.Errorf("%s: unexpected fields: %#v", version, created[0])
+		}
+	}
+
+	var action Action
+	err := json.Unmarshal([]byte(`{"actionid": "7", "name": "a", "eventsource": "0", "status": "0", "maintenance_mode": "0"}`), &action)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if action.PauseSuppressed == nil || *action.PauseSuppressed {
+		t.Errorf("Unexpected action: %#v", action)
+	}
+}
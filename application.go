@@ -1,6 +1,9 @@
 package zabbix
 
 import (
+	"fmt"
+	"strconv"
+
 	"github.com/AlekSi/reflector"
 )
 
@@ -14,8 +17,15 @@ type Application struct {
 
 type Applications []Application
 
+// Application with number of its items.
+type ApplicationCount struct {
+	Application
+	ItemCount int
+}
+
 // Wrapper for application.get: https://www.zabbix.com/documentation/2.0/manual/appendix/api/application/get
 func (api *API) ApplicationsGet(params Params) (res Applications, err error) {
+	params = params.Clone()
 	if _, present := params["output"]; !present {
 		params["output"] = "extend"
 	}
@@ -28,6 +38,39 @@ func (api *API) ApplicationsGet(params Params) (res Applications, err error) {
 	return
 }
 
+// Gets applications with number of items in each of them using selectItems=count.
+func (api *API) ApplicationsGetWithItemCount(params Params) (res []ApplicationCount, err error) {
+	params = params.Clone()
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	params["selectItems"] = "count"
+	response, err := api.CallWithError("application.get", params)
+	if err != nil {
+		return
+	}
+
+	results := response.Result.([]interface{})
+	var apps Applications
+	reflector.MapsToStructs2(results, &apps, reflector.Strconv, "json")
+	res = make([]ApplicationCount, len(apps))
+	for i, result := range results {
+		res[i].Application = apps[i]
+		// count is a string like "7"
+		switch count := result.(map[string]interface{})["items"].(type) {
+		case string:
+			res[i].ItemCount, err = strconv.Atoi(count)
+			if err != nil {
+				err = fmt.Errorf("Failed to parse item count %q of application %s.", count, apps[i].ApplicationId)
+				return
+			}
+		case float64:
+			res[i].ItemCount = int(count)
+		}
+	}
+	return
+}
+
 // Gets application by Id only if there is exactly 1 matching application.
 func (api *API) ApplicationGetById(id string) (res *Application, err error) {
 	apps, err := api.ApplicationsGet(Params{"applicationids": id})
@@ -67,10 +110,12 @@ func (api *API) ApplicationsCreate(apps Applications) (err error) {
 		return
 	}
 
-	result := response.Result.(map[string]interface{})
-	applicationids := result["applicationids"].([]interface{})
+	applicationids, err := response.ResultIDs("applicationids")
+	if err != nil {
+		return
+	}
 	for i, id := range applicationids {
-		apps[i].ApplicationId = id.(string)
+		apps[i].ApplicationId = id
 	}
 	return
 }
@@ -99,8 +144,10 @@ func (api *API) ApplicationsDeleteByIds(ids []string) (err error) {
 		return
 	}
 
-	result := response.Result.(map[string]interface{})
-	applicationids := result["applicationids"].([]interface{})
+	applicationids, err := response.ResultIDs("applicationids")
+	if err != nil {
+		return
+	}
 	if len(ids) != len(applicationids) {
 		err = &ExpectedMore{len(ids), len(applicationids)}
 	}
@@ -75,3 +75,23 @@ func TestApplications(t *testing.T) {
 
 	DeleteApplication(app, t)
 }
+
+func TestApplicationsGetWithItemCount(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		var params map[string]interface{}
+		call.decodeParams(&params, t)
+		if call.Method != "application.get" || params["selectItems"] != "count" || params["hostids"] != "10084" {
+			t.Errorf("Unexpected call %s: %#v", call.Method, params)
+		}
+		return []map[string]string{{"applicationid": "5", "hostid": "10084", "name": "CPU", "items": "7"}}
+	})
+	defer server.Close()
+
+	apps, err := api.ApplicationsGetWithItemCount(Params{"hostids": "10084"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(apps) != 1 || apps[0].ApplicationId != "5" || apps[0].Name != "CPU" || apps[0].ItemCount != 7 {
+		t.Errorf("Unexpected applications: %#v", apps)
+	}
+}
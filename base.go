@@ -2,18 +2,40 @@ package zabbix
 
 import (
 	"bytes"
+	"compress/gzip"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
+	"net"
 	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"sync/atomic"
+	"time"
 )
 
 type (
 	Params map[string]interface{}
 )
 
+// Returned by helpers getting objects by slice of Ids if it is empty: Zabbix would return all objects.
+var ErrEmptyIds = errors.New("Empty list of Ids.")
+
+// Returned by helpers deleting objects matched by parameters if parameters don't filter anything:
+// all objects would be deleted.
+var ErrEmptyFilter = errors.New("Parameters match all objects, add filter or \"all\": true.")
+
+// Returned by Login() if user has multi-factor authentication enabled (Zabbix 7.0+):
+// it can't be passed via API, so API token should be used for such users instead.
+var ErrMFARequired = errors.New("Multi-factor authentication is required, use API token instead.")
+
 type request struct {
 	Jsonrpc string      `json:"jsonrpc"`
 	Method  string      `json:"method"`
@@ -29,6 +51,55 @@ type Response struct {
 	Id      int32       `json:"id"`
 }
 
+// Extracts Ids stored under key in result, like "itemids" for item.create.
+// Some Zabbix versions return Ids as a map (keyed by index) instead of an array, both are handled.
+func (r *Response) ResultIDs(key string) (ids []string, err error) {
+	result, ok := r.Result.(map[string]interface{})
+	if !ok {
+		err = fmt.Errorf("Expected object in result, got %#v.", r.Result)
+		return
+	}
+
+	var values []interface{}
+	switch container := result[key].(type) {
+	case []interface{}:
+		values = container
+	case map[string]interface{}:
+		keys := make([]string, 0, len(container))
+		for k := range container {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			ki, erri := strconv.Atoi(keys[i])
+			kj, errj := strconv.Atoi(keys[j])
+			if erri != nil || errj != nil {
+				return keys[i] < keys[j]
+			}
+			return ki < kj
+		})
+		for _, k := range keys {
+			values = append(values, container[k])
+		}
+	default:
+		err = fmt.Errorf("Expected array or object in %s, got %#v.", key, result[key])
+		return
+	}
+
+	ids = make([]string, len(values))
+	for i, v := range values {
+		switch v := v.(type) {
+		case string:
+			ids[i] = v
+		case float64:
+			ids[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			err = fmt.Errorf("Expected Id in %s, got %#v.", key, v)
+			return
+		}
+	}
+	return
+}
+
 type Error struct {
 	Code    int    `json:"code"`
 	Message string `json:"message"`
@@ -39,6 +110,109 @@ func (e *Error) Error() string {
 	return fmt.Sprintf("%d (%s): %s", e.Code, e.Message, e.Data)
 }
 
+// Parameter validation failure extracted from Error.Data.
+type FieldError struct {
+	Path    string // like "/1/key_", empty if Data can't be parsed
+	Message string
+}
+
+func (e FieldError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// matches strings like `Invalid parameter "/1/key_": cannot be empty.` with any quotes
+var fieldErrorRE = regexp.MustCompile(`Invalid parameter ["']([^"']+)["']: (.+?)\.?$`)
+
+// Parses validation failures from Data. Its format varies across Zabbix versions,
+// so if it can't be parsed, single FieldError with raw Data as Message and empty Path is returned.
+func (e *Error) FieldErrors() []FieldError {
+	var res []FieldError
+	for _, line := range strings.Split(strings.TrimSpace(e.Data), "\n") {
+		m := fieldErrorRE.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			return []FieldError{{Message: e.Data}}
+		}
+		res = append(res, FieldError{Path: m[1], Message: m[2]})
+	}
+	return res
+}
+
+// JSON fields which are never logged.
+var sensitiveFields = map[string]bool{"password": true, "passwd": true, "privatekey": true, "tls_psk": true}
+
+// Returns copy of JSON request or response with values of sensitive fields replaced.
+func redact(b []byte) []byte {
+	var v interface{}
+	if json.Unmarshal(b, &v) != nil {
+		return b
+	}
+	res, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return b
+	}
+	return res
+}
+
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if sensitiveFields[strings.ToLower(key)] {
+				v[key] = "********"
+			} else {
+				v[key] = redactValue(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactValue(value)
+		}
+	}
+	return v
+}
+
+// Parses Unix timestamp like "1400000000" used by Zabbix for clocks. Empty string and "0" give zero time.
+func parseUnixTime(s string) (t time.Time, err error) {
+	if s == "" || s == "0" {
+		return
+	}
+	sec, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		err = fmt.Errorf("Failed to parse timestamp %q.", s)
+		return
+	}
+	t = time.Unix(sec, 0)
+	return
+}
+
+// Returns true if err is API error about already existing object.
+func isAlreadyExists(err error) bool {
+	e, ok := err.(*Error)
+	return ok && strings.Contains(e.Data, "already exists")
+}
+
+// Returns true if err is login error caused by multi-factor authentication of user.
+func (api *API) isMFAError(err error) bool {
+	e, ok := err.(*Error)
+	if !ok {
+		return false
+	}
+	data := strings.ToLower(e.Data)
+	if !strings.Contains(data, "mfa") && !strings.Contains(data, "multi-factor") && !strings.Contains(data, "two-factor") {
+		return false
+	}
+	v, err := api.serverVersion()
+	return err == nil && v.atLeast(7, 0)
+}
+
+// Returns true if err is API error about expired or invalid session.
+func isAuthError(err *Error) bool {
+	return err != nil && (strings.Contains(err.Data, "re-login") || strings.Contains(err.Data, "Not authori"))
+}
+
 type ExpectedOneResult int
 
 func (e *ExpectedOneResult) Error() string {
@@ -54,12 +228,82 @@ func (e *ExpectedMore) Error() string {
 	return fmt.Sprintf("Expected %d, got %d.", e.Expected, e.Got)
 }
 
+// Returned when request times out, see SetTimeout(). Dial is true if connection to server was not established,
+// false if server accepted request but didn't respond in time or response was read too slowly.
+type TimeoutError struct {
+	Method  string
+	Elapsed time.Duration
+	Dial    bool
+	Err     error // underlying error from http.Client
+}
+
+// Wraps err into TimeoutError if it's timeout one. Other errors are returned as is.
+func newTimeoutError(method string, start time.Time, err error) error {
+	var ne net.Error
+	if !errors.As(err, &ne) || !ne.Timeout() {
+		return err
+	}
+	var op *net.OpError
+	dial := errors.As(err, &op) && op.Op == "dial"
+	return &TimeoutError{method, time.Since(start), dial, err}
+}
+
+func (e *TimeoutError) Error() string {
+	stage := "waiting for response"
+	if e.Dial {
+		stage = "connecting"
+	}
+	return fmt.Sprintf("%s timed out after %s while %s.", e.Method, e.Elapsed.Round(time.Millisecond), stage)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// Returned when response is not a JSON-RPC one: HTTP error status or unexpected body like HTML login page.
+type TransportError struct {
+	StatusCode  int
+	ContentType string
+	Body        string // beginning of response body
+}
+
+func newTransportError(res *http.Response, body []byte) *TransportError {
+	if len(body) > 512 {
+		body = body[:512]
+	}
+	return &TransportError{res.StatusCode, res.Header.Get("Content-Type"), string(body)}
+}
+
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("Not a Zabbix JSON-RPC endpoint: HTTP status %d, content type %q.", e.StatusCode, e.ContentType)
+}
+
+// API access object. StrictDecode checks results of wrappers decoding them into typed structs, like UsersGet,
+// MediaTypesGet, GraphsGet, EventsGet or DiscoveryRulesGet. Results of wrappers using reflector (HostsGet,
+// TemplatesGet, HostGroupsGet, HostInterfacesGet) and of objects with own decoding (Item, Trigger, Proxy,
+// Settings; UserMedia and other nested ones) are not checked.
 type API struct {
-	Auth   string      // auth token, filled by Login()
-	Logger *log.Logger // request/response logger, nil by default
-	url    string
-	c      http.Client
-	id     int32
+	Auth                 string           // auth token, filled by Login()
+	Logger               *log.Logger      // request/response logger, nil by default
+	AutoReAuth           bool             // re-login and retry once if session expired, false by default
+	SkipExistingItems    bool             // make ItemsCreate skip items existing on their hosts, false by default
+	UpdateTemplatedItems bool             // make ItemsUpdate accept items inherited from templates, false by default
+	CheckTriggerItems    bool             // make TriggersCreate check items referenced by expressions, false by default
+	ItemGroupingMode     ItemGroupingMode // applications or Application tag for ItemsGetByApplicationIds, applications by default
+	StrictDecode         bool             // log result fields unknown to typed wrappers, false by default, see API
+	RetryPolicy          *RetryPolicy     // retry failed calls, nil (no retries) by default
+
+	url           string
+	user          string
+	password      string
+	c             http.Client
+	id            int32
+	apiToken      string
+	gzipResponses bool
+	gzipRequests  bool
+	headers       http.Header
+	version       *version
+	versionM      sync.Mutex
 }
 
 // Creates new API access object.
@@ -75,6 +319,51 @@ func (api *API) SetClient(c *http.Client) {
 	api.c = *c
 }
 
+// Sets time limit for every request, including connection and reading of response. Zero means no limit,
+// which is the default. Timed out calls return *TimeoutError. SetClient() replaces this limit with its client's one.
+func (api *API) SetTimeout(timeout time.Duration) {
+	api.c.Timeout = timeout
+}
+
+// Sets API token (Zabbix 5.4+) created in frontend. It is used instead of api.Auth, so Login() is not required.
+// On Zabbix 6.4+ it is sent in "Authorization: Bearer" header, on older versions in auth field,
+// so first call also detects Zabbix version.
+func (api *API) SetAPIToken(token string) {
+	api.apiToken = token
+}
+
+// Enables or disables gzip compression of responses: Accept-Encoding header is sent and gzipped responses are
+// decompressed. Request bodies are not compressed, see SetRequestCompression().
+func (api *API) SetCompression(enabled bool) {
+	api.gzipResponses = enabled
+}
+
+// Enables or disables gzip compression of request bodies with Content-Encoding header.
+// Not all frontends accept compressed requests, so it's separate from SetCompression().
+func (api *API) SetRequestCompression(enabled bool) {
+	api.gzipRequests = enabled
+}
+
+// Sets header sent with every request, like "X-Tenant-ID". Empty value removes header.
+// Headers set by this package itself (Content-Type, Authorization and others) are not overridden.
+func (api *API) SetHeader(key, value string) {
+	if api.headers == nil {
+		api.headers = make(http.Header)
+	}
+	if value == "" {
+		api.headers.Del(key)
+		return
+	}
+	api.headers.Set(key, value)
+}
+
+// Sets headers sent with every request, see SetHeader().
+func (api *API) SetHeaders(headers map[string]string) {
+	for key, value := range headers {
+		api.SetHeader(key, value)
+	}
+}
+
 func (api *API) printf(format string, v ...interface{}) {
 	if api.Logger != nil {
 		api.Logger.Printf(format, v...)
@@ -82,34 +371,123 @@ func (api *API) printf(format string, v ...interface{}) {
 }
 
 func (api *API) callBytes(method string, params interface{}) (b []byte, err error) {
-	id := atomic.AddInt32(&api.id, 1)
-	jsonobj := request{"2.0", method, params, api.Auth, id}
-	if method == "APIInfo.version" { // as of 2.4, this requires no auth param
-		jsonobj = request{"2.0", method, params, "", id}
+	b, err = api.callBytesRetrying(method, params)
+	if err != nil || !api.AutoReAuth || api.user == "" || strings.EqualFold(method, "user.login") {
+		return
+	}
+
+	var response struct {
+		Error *Error `json:"error"`
 	}
+	if json.Unmarshal(b, &response) != nil || !isAuthError(response.Error) {
+		return
+	}
+
+	// re-login and retry only once, Login() itself is never retried
+	api.printf("Session expired, logging in again")
+	api.Auth = ""
+	_, err = api.Login(api.user, api.password)
+	if err != nil {
+		return
+	}
+	return api.callBytesRetrying(method, params)
+}
+
+func (api *API) callBytesOnce(method string, params interface{}) (b []byte, err error) {
+	auth, bearer := api.Auth, false
+	if strings.EqualFold(method, "APIInfo.version") { // as of 2.4, this requires no auth param
+		auth = ""
+	} else if api.apiToken != "" {
+		var v version
+		v, err = api.serverVersion()
+		if err != nil {
+			return
+		}
+		auth, bearer = api.apiToken, v.atLeast(6, 4)
+		if bearer {
+			auth = ""
+		}
+	}
+
+	id := atomic.AddInt32(&api.id, 1)
+	jsonobj := request{"2.0", method, params, auth, id}
 	b, err = json.Marshal(jsonobj)
 	if err != nil {
 		return
 	}
-	api.printf("Request : %s", b)
+	if api.Logger != nil {
+		api.printf("Request : %s", redact(b))
+	}
 
-	req, err := http.NewRequest("POST", api.url, bytes.NewReader(b))
+	body := b
+	if api.gzipRequests {
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err = w.Write(b); err == nil {
+			err = w.Close()
+		}
+		if err != nil {
+			return
+		}
+		body = buf.Bytes()
+	}
+
+	req, err := http.NewRequest("POST", api.url, bytes.NewReader(body))
 	if err != nil {
 		return
 	}
-	req.ContentLength = int64(len(b))
+	req.ContentLength = int64(len(body))
 	req.Header.Add("Content-Type", "application/json-rpc")
 	req.Header.Add("User-Agent", "github.com/AlekSi/zabbix")
+	if api.gzipRequests {
+		req.Header.Add("Content-Encoding", "gzip")
+	}
+	if api.gzipResponses {
+		// when set explicitly, http.Transport doesn't decompress response transparently
+		req.Header.Add("Accept-Encoding", "gzip")
+	}
+	if bearer {
+		req.Header.Add("Authorization", "Bearer "+api.apiToken)
+	}
+	for key, values := range api.headers {
+		if _, present := req.Header[key]; !present {
+			req.Header[key] = values
+		}
+	}
 
+	start := time.Now()
 	res, err := api.c.Do(req)
 	if err != nil {
+		err = newTimeoutError(method, start, err)
 		api.printf("Error   : %s", err)
 		return
 	}
 	defer res.Body.Close()
 
-	b, err = ioutil.ReadAll(res.Body)
-	api.printf("Response: %s", b)
+	var r io.Reader = res.Body
+	if api.gzipResponses && strings.EqualFold(res.Header.Get("Content-Encoding"), "gzip") {
+		var gr *gzip.Reader
+		gr, err = gzip.NewReader(res.Body)
+		if err != nil {
+			return
+		}
+		defer gr.Close()
+		r = gr
+	}
+
+	b, err = ioutil.ReadAll(r)
+	if api.Logger != nil {
+		api.printf("Response: %s", redact(b))
+	}
+	if err != nil {
+		err = newTimeoutError(method, start, err)
+		return
+	}
+
+	trimmed := bytes.TrimSpace(b)
+	if res.StatusCode != http.StatusOK || len(trimmed) == 0 || trimmed[0] != '{' {
+		err = newTransportError(res, b)
+	}
 	return
 }
 
@@ -123,6 +501,116 @@ func (api *API) Call(method string, params interface{}) (response Response, err
 	return
 }
 
+// Calls specified API method and unmarshals response result into v.
+// Like CallWithError(), sets err to API error if any.
+func (api *API) callInto(method string, params interface{}, v interface{}) (err error) {
+	b, err := api.callBytes(method, params)
+	if err != nil {
+		return
+	}
+
+	var response struct {
+		Error  *Error          `json:"error"`
+		Result json.RawMessage `json:"result"`
+	}
+	err = json.Unmarshal(b, &response)
+	if err != nil {
+		return
+	}
+	if response.Error != nil {
+		err = response.Error
+		return
+	}
+	if api.StrictDecode {
+		api.checkUnknownFields(method, response.Result, v)
+	}
+	err = json.Unmarshal(response.Result, v)
+	return
+}
+
+// Logs fields of result which are not known to type of v, to help detect changes of Zabbix API.
+// Result is still decoded leniently by caller, so no data is lost. Fields of types with own
+// UnmarshalJSON method, like Item, Trigger and Proxy, are not checked.
+func (api *API) checkUnknownFields(method string, result json.RawMessage, v interface{}) {
+	var decoded interface{}
+	if json.Unmarshal(result, &decoded) != nil {
+		return
+	}
+
+	unknown := make(map[string]bool)
+	collectUnknownFields(decoded, reflect.TypeOf(v).Elem(), "", unknown)
+	fields := make([]string, 0, len(unknown))
+	for field := range unknown {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		api.printf("Warning: %s result has unknown field %q.", method, field)
+	}
+}
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// Adds to unknown fields of decoded JSON value which are not known to type t, like "name" or "hosts.name"
+// for nested ones. Fields are matched to struct fields case-insensitively, like encoding/json does.
+func collectUnknownFields(v interface{}, t reflect.Type, prefix string, unknown map[string]bool) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if reflect.PtrTo(t).Implements(unmarshalerType) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		if a, ok := v.([]interface{}); ok {
+			for _, e := range a {
+				collectUnknownFields(e, t.Elem(), prefix, unknown)
+			}
+		}
+	case reflect.Map:
+		if m, ok := v.(map[string]interface{}); ok {
+			for _, e := range m {
+				collectUnknownFields(e, t.Elem(), prefix, unknown)
+			}
+		}
+	case reflect.Struct:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return
+		}
+		fields := make(map[string]reflect.Type)
+		jsonFields(t, fields)
+		for key, value := range m {
+			ft, known := fields[strings.ToLower(key)]
+			if !known {
+				unknown[prefix+key] = true
+				continue
+			}
+			collectUnknownFields(value, ft, prefix+key+".", unknown)
+		}
+	}
+}
+
+// Adds JSON names of struct fields in lower case, including fields of embedded structs without name.
+func jsonFields(t reflect.Type, fields map[string]reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		switch {
+		case tag == "-":
+		case name == "" && f.Anonymous && f.Type.Kind() == reflect.Struct:
+			jsonFields(f.Type, fields)
+		case f.PkgPath != "": // unexported
+		case name == "":
+			fields[strings.ToLower(f.Name)] = f.Type
+		default:
+			fields[strings.ToLower(name)] = f.Type
+		}
+	}
+}
+
 // Uses Call() and then sets err to response.Error if former is nil and latter is not.
 func (api *API) CallWithError(method string, params interface{}) (response Response, err error) {
 	response, err = api.Call(method, params)
@@ -133,15 +621,63 @@ func (api *API) CallWithError(method string, params interface{}) (response Respo
 }
 
 // Calls "user.login" API method and fills api.Auth field.
+// Credentials are remembered for re-login if api.AutoReAuth is set.
 func (api *API) Login(user, password string) (auth string, err error) {
 	params := map[string]string{"user": user, "password": password}
 	response, err := api.CallWithError("user.login", params)
 	if err != nil {
+		if api.isMFAError(err) {
+			err = ErrMFARequired
+		}
 		return
 	}
 
 	auth = response.Result.(string)
 	api.Auth = auth
+	api.user, api.password = user, password
+	return
+}
+
+// Calls "user.logout" API method and cleans api.Auth field.
+func (api *API) Logout() (err error) {
+	_, err = api.CallWithError("user.logout", []string{})
+	if err == nil {
+		api.Auth = ""
+	}
+	return
+}
+
+// Logs out if session token is held (but not if API token is used) and closes idle HTTP connections.
+func (api *API) Close() (err error) {
+	if api.Auth != "" && api.apiToken == "" {
+		err = api.Logout()
+	}
+	api.c.CloseIdleConnections()
+	return
+}
+
+// Checks that URL points to Zabbix API without authentication and returns its version.
+// If it's something else, like HTML page of proxy, returns *TransportError.
+func (api *API) CheckEndpoint() (version string, err error) {
+	b, err := api.callBytesOnce("apiinfo.version", Params{})
+	if err != nil {
+		return
+	}
+
+	var response struct {
+		Jsonrpc string          `json:"jsonrpc"`
+		Error   *Error          `json:"error"`
+		Result  json.RawMessage `json:"result"`
+	}
+	if json.Unmarshal(b, &response) != nil || response.Jsonrpc != "2.0" {
+		err = &TransportError{StatusCode: http.StatusOK, Body: string(b)}
+		return
+	}
+	if response.Error != nil {
+		err = response.Error
+		return
+	}
+	err = json.Unmarshal(response.Result, &version)
 	return
 }
 
@@ -2,11 +2,19 @@ package zabbix_test
 
 import (
 	. "."
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
 	"log"
 	"math/rand"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"reflect"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 )
@@ -61,6 +69,47 @@ func getAPI(t *testing.T) *API {
 	return _api
 }
 
+// Request as seen by mock server.
+type mockCall struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Auth   string          `json:"auth"`
+	Id     int32           `json:"id"`
+	Header http.Header     `json:"-"`
+}
+
+// Decodes call params into v.
+func (c *mockCall) decodeParams(v interface{}, t *testing.T) {
+	if err := json.Unmarshal(c.Params, v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Creates API talking to fake JSON-RPC server. Handler's result is returned to client,
+// or sent as response error if it is *Error. Caller should close returned server.
+func newMockAPI(t *testing.T, handler func(call *mockCall) interface{}) (*API, *httptest.Server) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var call mockCall
+		if err := json.NewDecoder(r.Body).Decode(&call); err != nil {
+			t.Error(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		call.Header = r.Header
+
+		res := map[string]interface{}{"jsonrpc": "2.0", "id": call.Id}
+		result := handler(&call)
+		if e, ok := result.(*Error); ok {
+			res["error"] = e
+		} else {
+			res["result"] = result
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(res)
+	}))
+	return NewAPI(server.URL), server
+}
+
 func TestBadCalls(t *testing.T) {
 	api := getAPI(t)
 	res, err := api.Call("", nil)
@@ -90,3 +139,391 @@ func ExampleAPI_Call() {
 	res, _ := api.Call("item.get", Params{"itemids": "23970", "output": "extend"})
 	log.Print(res)
 }
+
+func TestResponseResultIDs(t *testing.T) {
+	for _, result := range []interface{}{
+		map[string]interface{}{"itemids": []interface{}{"23", "24", float64(25)}},
+		map[string]interface{}{"itemids": map[string]interface{}{"2": float64(25), "0": "23", "1": "24"}},
+	} {
+		res := Response{Result: result}
+		ids, err := res.ResultIDs("itemids")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(ids, []string{"23", "24", "25"}) {
+			t.Errorf("Unexpected ids for %#v: %#v", result, ids)
+		}
+	}
+
+	for _, result := range []interface{}{
+		nil,
+		true,
+		map[string]interface{}{"hostids": []interface{}{"23"}},
+		map[string]interface{}{"itemids": []interface{}{true}},
+	} {
+		res := Response{Result: result}
+		if _, err := res.ResultIDs("itemids"); err == nil {
+			t.Errorf("Expected error for %#v", result)
+		}
+	}
+}
+
+func TestDeleteWithMapResult(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		return map[string]interface{}{"itemids": map[string]string{"0": "23", "1": "24"}}
+	})
+	defer server.Close()
+
+	err := api.ItemsDeleteByIds([]string{"23", "24"})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAPIToken(t *testing.T) {
+	for _, version := range []string{"5.4.0", "6.4.0"} {
+		var call *mockCall
+		api, server := newMockAPI(t, func(c *mockCall) interface{} {
+			if c.Method == "apiinfo.version" || c.Method == "APIInfo.version" {
+				if c.Auth != "" || c.Header.Get("Authorization") != "" {
+					t.Errorf("%s: unexpected auth for version call: %#v", version, c)
+				}
+				return version
+			}
+			call = c
+			return []interface{}{}
+		})
+		api.SetAPIToken("token")
+
+		_, err := api.HostsGet(Params{})
+		server.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if version == "6.4.0" {
+			if call.Auth != "" || call.Header.Get("Authorization") != "Bearer token" {
+				t.Errorf("%s: expected bearer header, got %q and %q", version, call.Auth, call.Header.Get("Authorization"))
+			}
+		} else {
+			if call.Auth != "token" || call.Header.Get("Authorization") != "" {
+				t.Errorf("%s: expected auth field, got %q and %q", version, call.Auth, call.Header.Get("Authorization"))
+			}
+		}
+	}
+}
+
+func TestAutoReAuth(t *testing.T) {
+	var calls []string
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		calls = append(calls, call.Method+" "+call.Auth)
+		switch {
+		case call.Method == "user.login" && len(calls) == 1:
+			return "session1"
+		case call.Method == "user.login":
+			return "session2"
+		case call.Auth == "session1":
+			return &Error{Code: -32602, Message: "Invalid params.", Data: "Session terminated, re-login, please."}
+		default:
+			return []interface{}{}
+		}
+	})
+	defer server.Close()
+	api.AutoReAuth = true
+
+	_, err := api.Login("user", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = api.HostsGet(Params{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"user.login ", "host.get session1", "user.login ", "host.get session2"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("Unexpected calls: %#v", calls)
+	}
+}
+
+// Transport which counts CloseIdleConnections calls.
+type closeCountingTransport struct {
+	http.Transport
+	closed int
+}
+
+func (tr *closeCountingTransport) CloseIdleConnections() {
+	tr.closed++
+	tr.Transport.CloseIdleConnections()
+}
+
+func TestClose(t *testing.T) {
+	for _, token := range []bool{false, true} {
+		var methods []string
+		api, server := newMockAPI(t, func(call *mockCall) interface{} {
+			methods = append(methods, call.Method)
+			switch call.Method {
+			case "user.login":
+				return "session"
+			case "apiinfo.version", "APIInfo.version":
+				return "6.4.0"
+			default:
+				return true
+			}
+		})
+		tr := new(closeCountingTransport)
+		api.SetClient(&http.Client{Transport: tr})
+
+		var expected []string
+		if token {
+			api.SetAPIToken("token")
+		} else {
+			expected = []string{"user.login", "user.logout"}
+			if _, err := api.Login("user", "password"); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		err := api.Close()
+		server.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(methods, expected) {
+			t.Errorf("Expected %#v, got %#v", expected, methods)
+		}
+		if api.Auth != "" {
+			t.Errorf("Auth is not cleaned: %q", api.Auth)
+		}
+		if tr.closed != 1 {
+			t.Errorf("Expected CloseIdleConnections to be called once, got %d", tr.closed)
+		}
+	}
+}
+
+func TestCheckEndpoint(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		if call.Auth != "" {
+			t.Errorf("Unexpected auth %q", call.Auth)
+		}
+		return "6.0.0"
+	})
+	api.Auth = "session"
+	v, err := api.CheckEndpoint()
+	server.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != "6.0.0" {
+		t.Errorf("Unexpected version %q", v)
+	}
+
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><body>Please log in</body></html>"))
+	}))
+	defer server.Close()
+	_, err = NewAPI(server.URL).CheckEndpoint()
+	e, ok := err.(*TransportError)
+	if !ok {
+		t.Fatalf("Expected *TransportError, got %#v", err)
+	}
+	if e.StatusCode != 200 || e.ContentType != "text/html" {
+		t.Errorf("Unexpected error: %#v", e)
+	}
+}
+
+func TestErrorFieldErrors(t *testing.T) {
+	e := &Error{Code: -32602, Message: "Invalid params.", Data: `Invalid parameter "/1/key_": incorrect syntax near "[".`}
+	expected := []FieldError{{Path: "/1/key_", Message: `incorrect syntax near "["`}}
+	if !reflect.DeepEqual(e.FieldErrors(), expected) {
+		t.Errorf("Unexpected field errors: %#v", e.FieldErrors())
+	}
+
+	e = &Error{Code: -32602, Message: "Invalid params.", Data: "Item with key \"a\" already exists on \"host\"."}
+	expected = []FieldError{{Message: e.Data}}
+	if !reflect.DeepEqual(e.FieldErrors(), expected) {
+		t.Errorf("Unexpected field errors: %#v", e.FieldErrors())
+	}
+}
+
+func TestLoginMFA(t *testing.T) {
+	for version, mfa := range map[string]bool{"6.0.25": false, "7.0.0": true} {
+		api, server := newMockAPI(t, func(call *mockCall) interface{} {
+			switch call.Method {
+			case "apiinfo.version", "APIInfo.version":
+				return version
+			case "user.login":
+				return &Error{Code: -32500, Message: "Application error.", Data: "MFA is required for user, API login is not possible."}
+			}
+			t.Errorf("%s: unexpected method %s", version, call.Method)
+			return nil
+		})
+
+		_, err := api.Login("user", "password")
+		server.Close()
+		if mfa {
+			if err != ErrMFARequired {
+				t.Errorf("%s: expected ErrMFARequired, got %#v", version, err)
+			}
+		} else if _, ok := err.(*Error); !ok {
+			t.Errorf("%s: expected API error, got %#v", version, err)
+		}
+		if api.Auth != "" {
+			t.Errorf("%s: auth is set", version)
+		}
+	}
+}
+
+func TestCompression(t *testing.T) {
+	var gzipped bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gzipped = r.Header.Get("Content-Encoding") == "gzip"
+		var body io.Reader = r.Body
+		if gzipped {
+			gr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body = gr
+		}
+		var call mockCall
+		if err := json.NewDecoder(body).Decode(&call); err != nil {
+			t.Fatal(err)
+		}
+		res, _ := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": call.Id, "result": "6.0.0"})
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			w.Write(res)
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		gw := gzip.NewWriter(w)
+		gw.Write(res)
+		gw.Close()
+	}))
+	defer server.Close()
+
+	api := NewAPI(server.URL)
+	api.SetCompression(true)
+	v, err := api.Version()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != "6.0.0" || gzipped {
+		t.Errorf("Unexpected version %q, request gzipped: %v", v, gzipped)
+	}
+
+	api.SetRequestCompression(true)
+	v, err = api.Version()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != "6.0.0" || !gzipped {
+		t.Errorf("Unexpected version %q, request gzipped: %v", v, gzipped)
+	}
+
+	api.SetCompression(false)
+	v, err = api.Version()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != "6.0.0" || !gzipped {
+		t.Errorf("Unexpected version %q, request gzipped: %v", v, gzipped)
+	}
+}
+
+func TestSetHeaders(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		if call.Header.Get("X-Tenant-ID") != "acme" || call.Header.Get("X-Request-Source") != "ci" {
+			t.Errorf("Unexpected headers: %#v", call.Header)
+		}
+		if call.Header.Get("X-Removed") != "" {
+			t.Errorf("Unexpected removed header: %#v", call.Header)
+		}
+		if ct := call.Header["Content-Type"]; len(ct) != 1 || ct[0] != "application/json-rpc" {
+			t.Errorf("Unexpected Content-Type: %v", ct)
+		}
+		return "6.0.12"
+	})
+	defer server.Close()
+
+	api.SetHeader("X-Tenant-ID", "acme")
+	api.SetHeader("Content-Type", "text/plain")
+	api.SetHeader("X-Removed", "value")
+	api.SetHeaders(map[string]string{"X-Request-Source": "ci", "X-Removed": ""})
+	_, err := api.Version()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStrictDecode(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		// all unknown fields are reported, nested ones with prefix
+		return []map[string]interface{}{{"userid": "3", "username": "oncall", "roleid": 3, "attempt_failed": "2", "attempt_ip": "",
+			"usrgrps": []map[string]string{{"usrgrpid": "7", "name": "Ops", "mfaid": "0"}}}}
+	})
+	defer server.Close()
+	var buf bytes.Buffer
+	api.Logger = log.New(&buf, "", 0)
+
+	for _, strict := range []bool{false, true} {
+		buf.Reset()
+		api.StrictDecode = strict
+		users, err := api.UsersGet(Params{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(users) != 1 || users[0].Username != "oncall" {
+			t.Errorf("Unexpected users: %#v", users)
+		}
+		for _, field := range []string{"roleid", "attempt_failed", "attempt_ip", "usrgrps.name", "usrgrps.mfaid"} {
+			if warned := strings.Contains(buf.String(), `Warning: user.get result has unknown field "`+field+`".`); warned != strict {
+				t.Errorf("strict %v: unexpected log for %s:\n%s", strict, field, buf.String())
+			}
+		}
+		if strings.Contains(buf.String(), `unknown field "username"`) || strings.Contains(buf.String(), `unknown field "usrgrps.usrgrpid"`) {
+			t.Errorf("Unexpected log:\n%s", buf.String())
+		}
+	}
+
+	// raw calls are not checked
+	buf.Reset()
+	_, err := api.CallWithError("user.get", Params{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "Warning") {
+		t.Errorf("Unexpected log:\n%s", buf.String())
+	}
+}
+
+func TestTimeoutError(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		time.Sleep(200 * time.Millisecond)
+		return []map[string]string{}
+	})
+	defer server.Close()
+	api.SetTimeout(50 * time.Millisecond)
+
+	_, err := api.HostsGet(Params{})
+	e, ok := err.(*TimeoutError)
+	if !ok {
+		t.Fatalf("Expected timeout error, got %#v", err)
+	}
+	if e.Method != "host.get" || e.Elapsed < 50*time.Millisecond || e.Dial {
+		t.Errorf("Unexpected error: %#v", e)
+	}
+	if !strings.Contains(e.Error(), "host.get timed out") {
+		t.Errorf("Unexpected message: %s", e)
+	}
+
+	dialer := &net.Dialer{Timeout: time.Nanosecond}
+	api.SetClient(&http.Client{Transport: &http.Transport{DialContext: dialer.DialContext}})
+	_, err = api.HostsGet(Params{})
+	if e, ok = err.(*TimeoutError); !ok || e.Method != "host.get" || !e.Dial {
+		t.Errorf("Expected dial timeout error, got %#v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package zabbix
+
+import (
+	"net/url"
+	"path"
+	"strconv"
+	"time"
+)
+
+// Builds URL of frontend chart.php image with given items for given period (Zabbix 4.0+ time format).
+// It's not an API call: URL is built from API URL by replacing api_jsonrpc.php. HTTP basic auth credentials
+// of API URL are removed, as URL is meant to be embedded in dashboards or tickets.
+// URL doesn't carry the session: to fetch image, callers must attach api.Auth (filled by Login()) themselves
+// as zbx_sessionid cookie, like req.AddCookie(&http.Cookie{Name: "zbx_sessionid", Value: api.Auth}).
+// API tokens are not accepted there. Returns empty string if API URL can't be parsed.
+func (api *API) ChartURL(itemIds []string, from, to time.Time, width int) string {
+	u, err := url.Parse(api.url)
+	if err != nil {
+		return ""
+	}
+	u.User = nil
+	u.Path = path.Join(path.Dir(u.Path), "chart.php")
+
+	const layout = "2006-01-02 15:04:05"
+	q := url.Values{}
+	for _, id := range itemIds {
+		q.Add("itemids[]", id)
+	}
+	q.Set("from", from.Format(layout))
+	q.Set("to", to.Format(layout))
+	if width > 0 {
+		q.Set("width", strconv.Itoa(width))
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
@@ -0,0 +1,107 @@
+package zabbix
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Changes of one entity type (like "templates" or "items") which would be made by configuration.import.
+type ImportEntityDiff struct {
+	Added   []ImportChange `json:"added,omitempty"`
+	Removed []ImportChange `json:"removed,omitempty"`
+	Updated []ImportChange `json:"updated,omitempty"`
+}
+
+// Result of configuration.importcompare: entity type to its changes.
+type ImportDiff map[string]ImportEntityDiff
+
+// Single changed entity. Before is empty for added entities, After is empty for removed ones.
+// Nested contains changes of entities belonging to this one, like items of updated template.
+type ImportChange struct {
+	Before map[string]interface{}
+	After  map[string]interface{}
+	Nested ImportDiff
+}
+
+// Zabbix encodes empty objects as empty arrays.
+func isEmptyArray(b []byte) bool {
+	return bytes.Equal(bytes.Join(bytes.Fields(b), nil), []byte("[]"))
+}
+
+func (d *ImportEntityDiff) UnmarshalJSON(b []byte) error {
+	*d = ImportEntityDiff{}
+	if isEmptyArray(b) {
+		return nil
+	}
+	type plain ImportEntityDiff
+	return json.Unmarshal(b, (*plain)(d))
+}
+
+func (d *ImportDiff) UnmarshalJSON(b []byte) (err error) {
+	if isEmptyArray(b) {
+		*d = nil
+		return
+	}
+	var m map[string]ImportEntityDiff
+	err = json.Unmarshal(b, &m)
+	if err == nil {
+		*d = m
+	}
+	return
+}
+
+func (c *ImportChange) UnmarshalJSON(b []byte) (err error) {
+	var m map[string]json.RawMessage
+	err = json.Unmarshal(b, &m)
+	if err != nil {
+		return
+	}
+
+	*c = ImportChange{}
+	for key, value := range m {
+		switch key {
+		case "before":
+			err = unmarshalImportObject(value, &c.Before)
+		case "after":
+			err = unmarshalImportObject(value, &c.After)
+		default:
+			var diff ImportEntityDiff
+			err = json.Unmarshal(value, &diff)
+			if err == nil {
+				if c.Nested == nil {
+					c.Nested = make(ImportDiff)
+				}
+				c.Nested[key] = diff
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func unmarshalImportObject(b []byte, v *map[string]interface{}) error {
+	if isEmptyArray(b) {
+		return nil
+	}
+	return json.Unmarshal(b, v)
+}
+
+// Wrapper for configuration.importcompare: https://www.zabbix.com/documentation/6.0/manual/api/reference/configuration/importcompare
+// Returns changes configuration.import would make with the same arguments without making them.
+// The method exists since Zabbix 6.0, error is returned for earlier versions.
+func (api *API) ConfigurationImportCompare(format, source string, rules Params) (res ImportDiff, err error) {
+	v, err := api.serverVersion()
+	if err != nil {
+		return
+	}
+	if !v.atLeast(6, 0) {
+		err = fmt.Errorf("configuration.importcompare is not supported by Zabbix %s, 6.0 or later is required.", v)
+		return
+	}
+
+	err = api.callInto("configuration.importcompare", Params{"format": format, "source": source, "rules": rules}, &res)
+	return
+}
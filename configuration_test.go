@@ -0,0 +1,78 @@
+package zabbix_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	. "."
+)
+
+const importCompareResult = `{
+	"templates": {
+		"updated": [{
+			"before": {"uuid": "5aef", "template": "Template App", "name": "Template App"},
+			"after": {"uuid": "5aef", "template": "Template App", "name": "Template App renamed"},
+			"items": {
+				"added": [{"after": {"uuid": "7c1d", "name": "Free memory", "key": "vm.memory.size[free]"}}],
+				"removed": [{"before": {"uuid": "8a2b", "name": "Old item", "key": "old.key"}}]
+			}
+		}]
+	},
+	"template_groups": []
+}`
+
+func TestConfigurationImportCompare(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		switch call.Method {
+		case "apiinfo.version", "APIInfo.version":
+			return "6.0.3"
+		case "configuration.importcompare":
+			var params map[string]interface{}
+			call.decodeParams(&params, t)
+			if params["format"] != "yaml" || params["source"] != "zabbix_export: {}" || params["rules"] == nil {
+				t.Errorf("Unexpected params: %#v", params)
+			}
+			return json.RawMessage(importCompareResult)
+		}
+		t.Errorf("Unexpected method %s", call.Method)
+		return nil
+	})
+	defer server.Close()
+
+	diff, err := api.ConfigurationImportCompare("yaml", "zabbix_export: {}", Params{"templates": Params{"updateExisting": true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated := diff["templates"].Updated
+	if len(updated) != 1 || updated[0].Before["name"] != "Template App" || updated[0].After["name"] != "Template App renamed" {
+		t.Fatalf("Unexpected templates diff: %#v", diff["templates"])
+	}
+	items := updated[0].Nested["items"]
+	if len(items.Added) != 1 || items.Added[0].After["key"] != "vm.memory.size[free]" || items.Added[0].Before != nil {
+		t.Errorf("Unexpected added items: %#v", items.Added)
+	}
+	if len(items.Removed) != 1 || items.Removed[0].Before["key"] != "old.key" || items.Removed[0].After != nil {
+		t.Errorf("Unexpected removed items: %#v", items.Removed)
+	}
+	if groups, ok := diff["template_groups"]; !ok || !reflect.DeepEqual(groups, ImportEntityDiff{}) {
+		t.Errorf("Unexpected template groups diff: %#v", groups)
+	}
+}
+
+func TestConfigurationImportCompareOldVersion(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		if call.Method == "apiinfo.version" || call.Method == "APIInfo.version" {
+			return "5.4.9"
+		}
+		t.Errorf("Unexpected method %s", call.Method)
+		return nil
+	})
+	defer server.Close()
+
+	_, err := api.ConfigurationImportCompare("yaml", "zabbix_export: {}", Params{})
+	if err == nil {
+		t.Error("Expected error for Zabbix 5.4")
+	}
+}
@@ -0,0 +1,103 @@
+package zabbix
+
+import (
+	"fmt"
+)
+
+// Group of templates created by EnsureDependencies.
+const DefaultTemplateGroup = "Templates"
+
+// Prepares host groups and templates referenced by hosts before their bulk creation: gets Ids of existing ones
+// and creates missing ones. Missing templates are created in DefaultTemplateGroup.
+// Group or template created concurrently by someone else between get and create is fetched again.
+func (api *API) EnsureDependencies(groups, templates []string) (groupIds, templateIds map[string]string, err error) {
+	ids, err := api.HostGroupsEnsure(groups)
+	if err != nil {
+		return
+	}
+	groupIds = make(map[string]string, len(groups))
+	for i, name := range groups {
+		groupIds[name] = ids[i]
+	}
+
+	templateIds, err = api.templateIdsByHost(templates)
+	if err != nil {
+		return
+	}
+	var groupId string
+	for _, name := range templates {
+		if _, ok := templateIds[name]; ok {
+			continue
+		}
+		if groupId == "" {
+			groupId, err = api.ensureTemplateGroup(DefaultTemplateGroup)
+			if err != nil {
+				return
+			}
+		}
+
+		t := Templates{{Host: name, GroupIds: HostGroupIds{{GroupId: groupId}}}}
+		err = api.TemplatesCreate(t)
+		switch {
+		case err == nil:
+			templateIds[name] = t[0].TemplateId
+		case isAlreadyExists(err):
+			var created map[string]string
+			created, err = api.templateIdsByHost([]string{name})
+			if err != nil {
+				return
+			}
+			id, ok := created[name]
+			if !ok {
+				err = fmt.Errorf("Template %s already exists, but it's not found.", name)
+				return
+			}
+			templateIds[name] = id
+		default:
+			return
+		}
+	}
+	return
+}
+
+// Returns map of technical names to Ids of existing templates.
+func (api *API) templateIdsByHost(names []string) (res map[string]string, err error) {
+	res = make(map[string]string, len(names))
+	if len(names) == 0 {
+		return
+	}
+	templates, err := api.TemplatesGet(Params{"filter": map[string]interface{}{"host": names}, "output": []string{"templateid", "host"}})
+	if err != nil {
+		return
+	}
+	for _, t := range templates {
+		res[t.Host] = t.TemplateId
+	}
+	return
+}
+
+// Gets Id of group for templates, creating it if needed.
+func (api *API) ensureTemplateGroup(name string) (id string, err error) {
+	get := func() (string, error) {
+		groups, err := api.TemplateGroupsGet(Params{"filter": Params{"name": []string{name}}, "output": []string{"groupid"}})
+		if err != nil || len(groups) == 0 {
+			return "", err
+		}
+		return groups[0].GroupId, nil
+	}
+	if id, err = get(); err != nil || id != "" {
+		return
+	}
+
+	groups := TemplateGroups{{Name: name}}
+	err = api.TemplateGroupsCreate(groups)
+	switch {
+	case err == nil:
+		id = groups[0].GroupId
+	case isAlreadyExists(err):
+		if id, err = get(); err == nil && id == "" {
+			err = fmt.Errorf("Template group %s already exists, but it's not found.", name)
+		}
+	}
+	return
+}
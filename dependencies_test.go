@@ -0,0 +1,89 @@
+package zabbix_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "."
+)
+
+func TestEnsureDependencies(t *testing.T) {
+	var createdGroups, createdTemplates []string
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		switch call.Method {
+		case "apiinfo.version", "APIInfo.version":
+			return "5.0.10"
+		case "hostgroup.get":
+			var params map[string]interface{}
+			call.decodeParams(&params, t)
+			names := params["filter"].(map[string]interface{})["name"].([]interface{})
+			groups := []map[string]string{}
+			for _, name := range names {
+				switch name {
+				case "Linux servers":
+					groups = append(groups, map[string]string{"groupid": "2", "name": "Linux servers"})
+				case DefaultTemplateGroup:
+					groups = append(groups, map[string]string{"groupid": "1", "name": DefaultTemplateGroup})
+				}
+			}
+			return groups
+		case "hostgroup.create":
+			var groups []map[string]string
+			call.decodeParams(&groups, t)
+			createdGroups = append(createdGroups, groups[0]["name"])
+			return map[string]interface{}{"groupids": []string{"20"}}
+		case "template.get":
+			var params map[string]interface{}
+			call.decodeParams(&params, t)
+			hosts := params["filter"].(map[string]interface{})["host"].([]interface{})
+			templates := []map[string]string{}
+			for _, host := range hosts {
+				switch host {
+				case "Template OS Linux":
+					templates = append(templates, map[string]string{"templateid": "10001", "host": "Template OS Linux"})
+				case "Template Race":
+					if len(createdTemplates) != 0 {
+						templates = append(templates, map[string]string{"templateid": "10003", "host": "Template Race"})
+					}
+				}
+			}
+			return templates
+		case "template.create":
+			var templates []map[string]interface{}
+			call.decodeParams(&templates, t)
+			host := templates[0]["host"].(string)
+			createdTemplates = append(createdTemplates, host)
+			if !reflect.DeepEqual(templates[0]["groups"], []interface{}{map[string]interface{}{"groupid": "1"}}) {
+				t.Errorf("Unexpected template groups: %#v", templates[0])
+			}
+			if host == "Template Race" {
+				return &Error{Code: -32602, Message: "Invalid params.", Data: `Template "Template Race" already exists.`}
+			}
+			return map[string]interface{}{"templateids": []string{"10002"}}
+		}
+		t.Errorf("Unexpected method %s", call.Method)
+		return nil
+	})
+	defer server.Close()
+
+	groupIds, templateIds, err := api.EnsureDependencies(
+		[]string{"Linux servers", "Databases"},
+		[]string{"Template OS Linux", "Template App MySQL", "Template Race"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(groupIds, map[string]string{"Linux servers": "2", "Databases": "20"}) {
+		t.Errorf("Unexpected group ids: %#v", groupIds)
+	}
+	expected := map[string]string{"Template OS Linux": "10001", "Template App MySQL": "10002", "Template Race": "10003"}
+	if !reflect.DeepEqual(templateIds, expected) {
+		t.Errorf("Unexpected template ids: %#v", templateIds)
+	}
+	if !reflect.DeepEqual(createdGroups, []string{"Databases"}) {
+		t.Errorf("Unexpected created groups: %v", createdGroups)
+	}
+	if !reflect.DeepEqual(createdTemplates, []string{"Template App MySQL", "Template Race"}) {
+		t.Errorf("Unexpected created templates: %v", createdTemplates)
+	}
+}
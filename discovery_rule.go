@@ -0,0 +1,107 @@
+package zabbix
+
+import (
+	"encoding/json"
+)
+
+// Low-level discovery rule: https://www.zabbix.com/documentation/3.0/manual/api/reference/discoveryrule/object
+type DiscoveryRule struct {
+	ItemId      string   `json:"itemid,omitempty"`
+	HostId      string   `json:"hostid,omitempty"`
+	InterfaceId string   `json:"interfaceid,omitempty"`
+	Key         string   `json:"key_,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	Type        ItemType `json:"type,string"`
+	Delay       string   `json:"delay,omitempty"` // seconds or, in Zabbix 3.4+, time suffix like "1h"
+	Status      Status   `json:"status,string"`
+
+	// Read-only fields with the same meaning as for items: NotSupportedState means rule can't be
+	// processed, Error tells why. Not sent on create or update.
+	State ItemState `json:"-"`
+	Error string    `json:"-"`
+}
+
+// Decodes read-only state and error.
+func (rule *DiscoveryRule) UnmarshalJSON(b []byte) (err error) {
+	type plain DiscoveryRule
+	aux := struct {
+		*plain
+		State ItemState `json:"state,string"`
+		Error string    `json:"error"`
+	}{plain: (*plain)(rule)}
+	err = json.Unmarshal(b, &aux)
+	if err != nil {
+		return
+	}
+
+	rule.State, rule.Error = aux.State, aux.Error
+	return
+}
+
+type DiscoveryRules []DiscoveryRule
+
+// Wrapper for discoveryrule.get: https://www.zabbix.com/documentation/3.0/manual/api/reference/discoveryrule/get
+func (api *API) DiscoveryRulesGet(params Params) (res DiscoveryRules, err error) {
+	params = params.Clone()
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	err = api.callInto("discoveryrule.get", params, &res)
+	return
+}
+
+// Gets discovery rules of given host in NotSupportedState, with their errors.
+func (api *API) DiscoveryRulesGetBroken(hostId string) (res DiscoveryRules, err error) {
+	return api.DiscoveryRulesGet(Params{"hostids": hostId, "filter": Params{"state": NotSupportedState}})
+}
+
+// Wrapper for discoveryrule.create: https://www.zabbix.com/documentation/3.0/manual/api/reference/discoveryrule/create
+func (api *API) DiscoveryRulesCreate(rules DiscoveryRules) (err error) {
+	response, err := api.CallWithError("discoveryrule.create", rules)
+	if err != nil {
+		return
+	}
+
+	itemids, err := response.ResultIDs("itemids")
+	if err != nil {
+		return
+	}
+	for i, id := range itemids {
+		rules[i].ItemId = id
+	}
+	return
+}
+
+// Wrapper for discoveryrule.delete: https://www.zabbix.com/documentation/3.0/manual/api/reference/discoveryrule/delete
+// Cleans ItemId in all rules elements if call succeed.
+func (api *API) DiscoveryRulesDelete(rules DiscoveryRules) (err error) {
+	ids := make([]string, len(rules))
+	for i, rule := range rules {
+		ids[i] = rule.ItemId
+	}
+
+	err = api.DiscoveryRulesDeleteByIds(ids)
+	if err == nil {
+		for i := range rules {
+			rules[i].ItemId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for discoveryrule.delete: https://www.zabbix.com/documentation/3.0/manual/api/reference/discoveryrule/delete
+func (api *API) DiscoveryRulesDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("discoveryrule.delete", ids)
+	if err != nil {
+		return
+	}
+
+	itemids, err := response.ResultIDs("ruleids")
+	if err != nil {
+		return
+	}
+	if len(ids) != len(itemids) {
+		err = &ExpectedMore{len(ids), len(itemids)}
+	}
+	return
+}
@@ -0,0 +1,52 @@
+package zabbix_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	. "."
+)
+
+func TestDiscoveryRulesGetBroken(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		if call.Method != "discoveryrule.get" {
+			t.Errorf("Unexpected method %s", call.Method)
+			return nil
+		}
+		var params map[string]interface{}
+		call.decodeParams(&params, t)
+		if params["hostids"] != "10084" || !reflect.DeepEqual(params["filter"], map[string]interface{}{"state": float64(1)}) {
+			t.Errorf("Unexpected params: %#v", params)
+		}
+		return []map[string]string{{
+			"itemid": "500", "hostid": "10084", "key_": "vfs.fs.discovery", "name": "Mounted filesystems",
+			"type": "0", "status": "0", "state": "1", "error": "Value should be a JSON object or array.",
+		}}
+	})
+	defer server.Close()
+
+	rules, err := api.DiscoveryRulesGetBroken("10084")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 1 || rules[0].State != NotSupportedState || rules[0].Error != "Value should be a JSON object or array." {
+		t.Errorf("Unexpected rules: %#v", rules)
+	}
+
+	// read-only fields are not sent back
+	b, err := json.Marshal(rules[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]interface{}
+	if err = json.Unmarshal(b, &m); err != nil {
+		t.Fatal(err)
+	}
+	if _, present := m["error"]; present {
+		t.Errorf("Unexpected error field: %s", b)
+	}
+	if _, present := m["state"]; present {
+		t.Errorf("Unexpected state field: %s", b)
+	}
+}
@@ -0,0 +1,131 @@
+package zabbix
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Host and item data for built-in macros.
+type macroContext struct {
+	Hosts []struct {
+		Host string `json:"host"`
+		Name string `json:"name"`
+	} `json:"hosts"`
+	Interfaces []struct {
+		IP    string `json:"ip"`
+		DNS   string `json:"dns"`
+		UseIP string `json:"useip"`
+	} `json:"interfaces"`
+	Items []struct {
+		Name      string `json:"name"`
+		Key       string `json:"key_"`
+		LastValue string `json:"lastvalue"`
+	} `json:"items"`
+
+	// item fields
+	Name      string `json:"name"`
+	Key       string `json:"key_"`
+	LastValue string `json:"lastvalue"`
+
+	// trigger field
+	Description string `json:"description"`
+}
+
+// Returns values of macros known from context.
+func (m *macroContext) values() map[string]string {
+	res := make(map[string]string)
+	if len(m.Hosts) != 0 {
+		res["{HOST.HOST}"] = m.Hosts[0].Host
+		res["{HOST.NAME}"] = m.Hosts[0].Name
+	}
+	if len(m.Interfaces) != 0 {
+		iface := m.Interfaces[0]
+		res["{HOST.IP}"] = iface.IP
+		res["{HOST.DNS}"] = iface.DNS
+		res["{HOST.CONN}"] = iface.DNS
+		if iface.UseIP == "1" {
+			res["{HOST.CONN}"] = iface.IP
+		}
+	}
+	if m.Description != "" {
+		res["{TRIGGER.NAME}"] = m.Description
+		if len(m.Items) != 0 {
+			m.Name, m.Key, m.LastValue = m.Items[0].Name, m.Items[0].Key, m.Items[0].LastValue
+		}
+	}
+	if m.Key != "" {
+		res["{ITEM.NAME}"] = m.Name
+		res["{ITEM.KEY}"] = m.Key
+		res["{ITEM.LASTVALUE}"] = m.LastValue
+	}
+	return res
+}
+
+// Substitutes built-in macros like {HOST.NAME}, {HOST.IP} and {ITEM.LASTVALUE} in text for display,
+// fetching host, interface and item data of object. objectType is "item" or "trigger";
+// item macros of trigger use its first item. Unknown macros and macros without value,
+// like {HOST.IP} of item without interface, are left as is.
+func (api *API) ResolveDisplayMacros(ctx context.Context, objectType string, id string, text string) (res string, err error) {
+	if !strings.Contains(text, "{") {
+		return text, nil
+	}
+
+	var method string
+	params := Params{"selectHosts": []string{"host", "name"}}
+	switch objectType {
+	case "item":
+		method = "item.get"
+		params["itemids"] = id
+		params["output"] = []string{"name", "key_", "lastvalue"}
+		params["selectInterfaces"] = []string{"ip", "dns", "useip"}
+	case "trigger":
+		method = "trigger.get"
+		params["triggerids"] = id
+		params["output"] = []string{"description"}
+		params["selectItems"] = []string{"name", "key_", "lastvalue"}
+	default:
+		err = fmt.Errorf("Unknown object type %q, expected item or trigger.", objectType)
+		return
+	}
+
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	var objects []macroContext
+	err = api.callInto(method, params, &objects)
+	if err != nil {
+		return
+	}
+	if len(objects) != 1 {
+		e := ExpectedOneResult(len(objects))
+		err = &e
+		return
+	}
+	obj := &objects[0]
+
+	// triggers have no interface, use main agent one of first host
+	if objectType == "trigger" && strings.Contains(text, "{HOST.") {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		err = api.callInto("hostinterface.get", Params{
+			"triggerids": id,
+			"filter":     Params{"main": 1, "type": Agent},
+			"output":     []string{"ip", "dns", "useip"},
+		}, &obj.Interfaces)
+		if err != nil {
+			return
+		}
+	}
+
+	values := obj.values()
+	pairs := make([]string, 0, len(values)*2)
+	for macro, value := range values {
+		if value != "" {
+			pairs = append(pairs, macro, value)
+		}
+	}
+	res = strings.NewReplacer(pairs...).Replace(text)
+	return
+}
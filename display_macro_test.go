@@ -0,0 +1,59 @@
+package zabbix_test
+
+import (
+	"context"
+	"testing"
+)
+
+func TestResolveDisplayMacros(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		var params map[string]interface{}
+		call.decodeParams(&params, t)
+		switch call.Method {
+		case "item.get":
+			if params["itemids"] != "23" || params["selectHosts"] == nil || params["selectInterfaces"] == nil {
+				t.Errorf("Unexpected params: %#v", params)
+			}
+			return []interface{}{map[string]interface{}{
+				"name": "Free disk space", "key_": "vfs.fs.size[/,free]", "lastvalue": "1024",
+				"hosts":      []map[string]string{{"host": "db1", "name": "Database 1"}},
+				"interfaces": []map[string]string{},
+			}}
+		case "trigger.get":
+			return []interface{}{map[string]interface{}{
+				"description": "Low disk space",
+				"hosts":       []map[string]string{{"host": "db1", "name": "Database 1"}},
+				"items":       []map[string]string{{"name": "Free disk space", "key_": "vfs.fs.size[/,free]", "lastvalue": "0"}},
+			}}
+		case "hostinterface.get":
+			if params["triggerids"] != "13" {
+				t.Errorf("Unexpected params: %#v", params)
+			}
+			return []map[string]string{{"ip": "10.0.0.1", "dns": "db1.example.com", "useip": "1"}}
+		}
+		t.Errorf("Unexpected method %s", call.Method)
+		return nil
+	})
+	defer server.Close()
+
+	text, err := api.ResolveDisplayMacros(context.Background(), "item", "23", "{HOST.NAME}: {ITEM.LASTVALUE} bytes free on {HOST.IP}, {$DISK.MIN} {FOO.BAR}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "Database 1: 1024 bytes free on {HOST.IP}, {$DISK.MIN} {FOO.BAR}"; text != expected {
+		t.Errorf("Expected %q, got %q", expected, text)
+	}
+
+	text, err = api.ResolveDisplayMacros(context.Background(), "trigger", "13", "{TRIGGER.NAME} on {HOST.NAME} ({HOST.CONN}): {ITEM.LASTVALUE}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "Low disk space on Database 1 (10.0.0.1): 0"; text != expected {
+		t.Errorf("Expected %q, got %q", expected, text)
+	}
+
+	_, err = api.ResolveDisplayMacros(context.Background(), "graph", "1", "{HOST.NAME}")
+	if err == nil {
+		t.Error("Expected error for unknown object type")
+	}
+}
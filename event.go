@@ -0,0 +1,258 @@
+package zabbix
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+type (
+	EventSource int
+	EventObject int
+	EventValue  int
+
+	// Set of acknowledge actions, see AcknowledgeAction constants (Zabbix 4.0+).
+	AcknowledgeAction int
+
+	// Event tags are inherited from trigger.
+	EventTag = Tag
+)
+
+const (
+	TriggerEventSource          EventSource = 0
+	DiscoveryEventSource        EventSource = 1
+	AutoRegistrationEventSource EventSource = 2
+	InternalEventSource         EventSource = 3
+
+	TriggerEventObject       EventObject = 0
+	DiscoveredHostObject     EventObject = 1
+	DiscoveredServiceObject  EventObject = 2
+	AutoRegisteredHostObject EventObject = 3
+
+	OKEvent      EventValue = 0
+	ProblemEvent EventValue = 1
+
+	CloseProblemAction   AcknowledgeAction = 1
+	AcknowledgeEvent     AcknowledgeAction = 2
+	AddMessageAction     AcknowledgeAction = 4
+	ChangeSeverityAction AcknowledgeAction = 8
+	UnacknowledgeEvent   AcknowledgeAction = 16 // Zabbix 5.0+
+)
+
+// https://www.zabbix.com/documentation/3.2/manual/api/reference/event/object#acknowledge
+type Acknowledge struct {
+	AcknowledgeId string `json:"acknowledgeid"`
+	UserId        string `json:"userid"`
+	EventId       string `json:"eventid"`
+	Clock         string `json:"clock"`
+	Message       string `json:"message"`
+
+	// Actions made by acknowledge (Zabbix 4.0+), zero for older versions.
+	Action AcknowledgeAction `json:"action,string,omitempty"`
+}
+
+// Returns time of acknowledge.
+func (a Acknowledge) Time() (time.Time, error) {
+	return parseUnixTime(a.Clock)
+}
+
+// Returns true if acknowledge made given action.
+func (a Acknowledge) Has(action AcknowledgeAction) bool {
+	return a.Action&action != 0
+}
+
+type Acknowledges []Acknowledge
+
+// Sorts acknowledges by clock, oldest first.
+func (acks Acknowledges) sort() (err error) {
+	for _, a := range acks {
+		if _, err = a.Time(); err != nil {
+			return
+		}
+	}
+	sort.SliceStable(acks, func(i, j int) bool {
+		ti, _ := acks[i].Time()
+		tj, _ := acks[j].Time()
+		return ti.Before(tj)
+	})
+	return
+}
+
+// https://www.zabbix.com/documentation/3.2/manual/api/reference/event/object
+type Event struct {
+	EventId      string      `json:"eventid"`
+	Source       EventSource `json:"source,string"`
+	Object       EventObject `json:"object,string"`
+	ObjectId     string      `json:"objectid"`
+	Clock        string      `json:"clock"`
+	Value        EventValue  `json:"value,string"`
+	Acknowledged int         `json:"acknowledged,string"`
+
+	// Id of recovery event, "0" if problem is not resolved (Zabbix 3.2+).
+	REventId string `json:"r_eventid,omitempty"`
+
+	// Fields below returned by select_acknowledges and selectRelatedObject query parameters.
+	Acknowledges  Acknowledges `json:"acknowledges,omitempty"`
+	RelatedObject *Trigger     `json:"relatedObject,omitempty"`
+
+	// Returned by selectTags query parameter (Zabbix 3.2+): empty if event has no tags, nil if not selected.
+	Tags []EventTag `json:"tags,omitempty"`
+}
+
+type Events []Event
+
+// Problem event paired with its recovery.
+type ProblemTimeline struct {
+	Problem       Event
+	RecoveryClock *time.Time // nil if problem is still open
+}
+
+// Returns true if problem is not resolved yet.
+func (p *ProblemTimeline) Open() bool {
+	return p.RecoveryClock == nil
+}
+
+// Wrapper for event.get: https://www.zabbix.com/documentation/3.2/manual/api/reference/event/get
+func (api *API) EventsGet(params Params) (res Events, err error) {
+	params = params.Clone()
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	err = api.callInto("event.get", params, &res)
+	return
+}
+
+// Gets events with all their acknowledges and comments, oldest acknowledge first.
+// Returns ErrEmptyIds if eventIds is empty.
+func (api *API) EventsGetWithAcks(eventIds []string) (res Events, err error) {
+	if len(eventIds) == 0 {
+		err = ErrEmptyIds
+		return
+	}
+	res, err = api.EventsGet(Params{"eventids": eventIds, "select_acknowledges": "extend"})
+	if err != nil {
+		return
+	}
+	for i := range res {
+		err = res[i].Acknowledges.sort()
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Gets events between from and to (inclusive) requesting at most pageSize events per event.get call.
+// Windows are advanced by clock of the last received event, and events at the boundary clock
+// received twice are returned once. If more than pageSize events share the same clock,
+// all of them are requested without limit. pageSize must be positive.
+func (api *API) EventsGetRange(from, to time.Time, pageSize int) (res Events, err error) {
+	if pageSize < 1 {
+		err = fmt.Errorf("Bad page size %d: must be positive.", pageSize)
+		return
+	}
+
+	seen := make(map[string]bool)
+	add := func(events Events) {
+		for _, e := range events {
+			if !seen[e.EventId] {
+				seen[e.EventId] = true
+				res = append(res, e)
+			}
+		}
+	}
+	params := func(from, to int64) Params {
+		return Params{"time_from": from, "time_till": to, "sortfield": []string{"clock", "eventid"}, "sortorder": "ASC"}
+	}
+
+	cursor, till := from.Unix(), to.Unix()
+	for cursor <= till {
+		p := params(cursor, till)
+		p["limit"] = pageSize
+		var events Events
+		events, err = api.EventsGet(p)
+		if err != nil {
+			return
+		}
+		add(events)
+		if len(events) < pageSize {
+			return
+		}
+
+		var last int64
+		last, err = strconv.ParseInt(events[len(events)-1].Clock, 10, 64)
+		if err != nil {
+			err = fmt.Errorf("Failed to parse event clock %q.", events[len(events)-1].Clock)
+			return
+		}
+		if last > cursor {
+			cursor = last
+			continue
+		}
+
+		// whole page has the same clock
+		events, err = api.EventsGet(params(cursor, cursor))
+		if err != nil {
+			return
+		}
+		add(events)
+		cursor++
+	}
+	return
+}
+
+// Gets events with tag equal to value, with their tags selected.
+func (api *API) EventsGetByTag(tag, value string) (res Events, err error) {
+	filters := TagFilters{{Tag: tag, Value: value, Operator: TagEquals}}
+	return api.EventsGet(filters.params(Params{"selectTags": "extend"}, EvalAndOr))
+}
+
+// Gets problem events with acknowledges and related triggers, and pairs them with recovery events (Zabbix 3.2+).
+// Recovery clocks are fetched with one more event.get call.
+func (api *API) EventsGetProblemsWithRecovery(params Params) (res []ProblemTimeline, err error) {
+	params = params.Clone()
+	if _, present := params["value"]; !present {
+		params["value"] = ProblemEvent
+	}
+	if _, present := params["select_acknowledges"]; !present {
+		params["select_acknowledges"] = "extend"
+	}
+	if _, present := params["selectRelatedObject"]; !present {
+		params["selectRelatedObject"] = "extend"
+	}
+	problems, err := api.EventsGet(params)
+	if err != nil {
+		return
+	}
+
+	var recoveryIds []string
+	for _, problem := range problems {
+		if problem.REventId != "" && problem.REventId != "0" {
+			recoveryIds = append(recoveryIds, problem.REventId)
+		}
+	}
+	clocks := make(map[string]time.Time, len(recoveryIds))
+	if len(recoveryIds) != 0 {
+		var recoveries Events
+		recoveries, err = api.EventsGet(Params{"eventids": recoveryIds, "output": []string{"eventid", "clock"}})
+		if err != nil {
+			return
+		}
+		for _, recovery := range recoveries {
+			clocks[recovery.EventId], err = parseUnixTime(recovery.Clock)
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	res = make([]ProblemTimeline, len(problems))
+	for i, problem := range problems {
+		res[i].Problem = problem
+		if clock, ok := clocks[problem.REventId]; ok {
+			res[i].RecoveryClock = &clock
+		}
+	}
+	return
+}
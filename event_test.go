@@ -0,0 +1,187 @@
+package zabbix_test
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	. "."
+)
+
+func TestEventsGetProblemsWithRecovery(t *testing.T) {
+	var calls int
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		calls++
+		var params map[string]interface{}
+		call.decodeParams(&params, t)
+		switch calls {
+		case 1:
+			if params["value"] != float64(1) || params["select_acknowledges"] != "extend" || params["selectRelatedObject"] != "extend" {
+				t.Errorf("Unexpected params: %#v", params)
+			}
+			return []interface{}{
+				map[string]interface{}{
+					"eventid": "10", "source": "0", "object": "0", "objectid": "13", "clock": "1400000000",
+					"value": "1", "acknowledged": "1", "r_eventid": "12",
+					"acknowledges":  []map[string]string{{"acknowledgeid": "1", "userid": "1", "eventid": "10", "clock": "1400000100", "message": "On it"}},
+					"relatedObject": map[string]string{"triggerid": "13", "description": "Disk is full", "priority": "4", "status": "0"},
+				},
+				map[string]interface{}{
+					"eventid": "11", "source": "0", "object": "0", "objectid": "14", "clock": "1400000200",
+					"value": "1", "acknowledged": "0", "r_eventid": "0",
+				},
+			}
+		case 2:
+			if !reflect.DeepEqual(params["eventids"], []interface{}{"12"}) {
+				t.Errorf("Unexpected params: %#v", params)
+			}
+			return []map[string]string{{"eventid": "12", "clock": "1400000500"}}
+		}
+		t.Errorf("Unexpected call %d", calls)
+		return nil
+	})
+	defer server.Close()
+
+	timelines, err := api.EventsGetProblemsWithRecovery(Params{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(timelines) != 2 {
+		t.Fatalf("Unexpected timelines: %#v", timelines)
+	}
+
+	resolved, open := timelines[0], timelines[1]
+	if resolved.Open() || !resolved.RecoveryClock.Equal(time.Unix(1400000500, 0)) {
+		t.Errorf("Bad resolved problem: %#v", resolved)
+	}
+	if len(resolved.Problem.Acknowledges) != 1 || resolved.Problem.RelatedObject.Priority != High {
+		t.Errorf("Bad resolved problem: %#v", resolved.Problem)
+	}
+	if !open.Open() || open.Problem.EventId != "11" {
+		t.Errorf("Bad open problem: %#v", open)
+	}
+}
+
+func TestEventsGetByTag(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		var params map[string]interface{}
+		call.decodeParams(&params, t)
+		expected := []interface{}{map[string]interface{}{"tag": "service", "value": "db", "operator": float64(1)}}
+		if call.Method != "event.get" || !reflect.DeepEqual(params["tags"], expected) || params["selectTags"] != "extend" {
+			t.Errorf("Unexpected call %s: %#v", call.Method, params)
+		}
+		return []map[string]interface{}{
+			{"eventid": "1", "source": "0", "object": "0", "value": "1", "acknowledged": "0",
+				"tags": []map[string]string{{"tag": "service", "value": "db"}, {"tag": "env", "value": "prod"}}},
+			{"eventid": "2", "source": "0", "object": "0", "value": "1", "acknowledged": "0", "tags": []interface{}{}},
+			{"eventid": "3", "source": "0", "object": "0", "value": "1", "acknowledged": "0"},
+		}
+	})
+	defer server.Close()
+
+	events, err := api.EventsGetByTag("service", "db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("Unexpected events: %#v", events)
+	}
+	expected := []EventTag{{Tag: "service", Value: "db"}, {Tag: "env", Value: "prod"}}
+	if !reflect.DeepEqual(events[0].Tags, expected) {
+		t.Errorf("Unexpected tags: %#v", events[0].Tags)
+	}
+	if events[1].Tags == nil || len(events[1].Tags) != 0 || events[2].Tags != nil {
+		t.Errorf("Unexpected tags: %#v %#v", events[1].Tags, events[2].Tags)
+	}
+}
+
+func TestEventsGetRange(t *testing.T) {
+	// clock 110 has more events than page size
+	clocks := []int64{100, 101, 105, 105, 105, 106, 110, 110, 110, 110, 111, 120}
+	var calls int
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		calls++
+		var params struct {
+			From  int64 `json:"time_from"`
+			Till  int64 `json:"time_till"`
+			Limit int   `json:"limit"`
+		}
+		call.decodeParams(&params, t)
+		res := []map[string]string{}
+		for i, clock := range clocks {
+			if clock >= params.From && clock <= params.Till && (params.Limit == 0 || len(res) < params.Limit) {
+				res = append(res, map[string]string{"eventid": strconv.Itoa(i + 1), "clock": strconv.FormatInt(clock, 10), "source": "0", "object": "0", "value": "1", "acknowledged": "0"})
+			}
+		}
+		return res
+	})
+	defer server.Close()
+
+	events, err := api.EventsGetRange(time.Unix(101, 0), time.Unix(115, 0), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, e := range events {
+		ids = append(ids, e.EventId)
+	}
+	expected := []string{"2", "3", "4", "5", "6", "7", "8", "9", "10", "11"}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Errorf("Expected %v, got %v", expected, ids)
+	}
+	if calls > 10 {
+		t.Errorf("Too many calls: %d", calls)
+	}
+
+	calls = 0
+	for _, pageSize := range []int{0, -1} {
+		_, err = api.EventsGetRange(time.Unix(101, 0), time.Unix(115, 0), pageSize)
+		if err == nil {
+			t.Errorf("Expected error for page size %d", pageSize)
+		}
+	}
+	if calls != 0 {
+		t.Errorf("Unexpected calls: %d", calls)
+	}
+}
+
+func TestEventsGetWithAcks(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		var params map[string]interface{}
+		call.decodeParams(&params, t)
+		if call.Method != "event.get" || params["select_acknowledges"] != "extend" {
+			t.Errorf("Unexpected call %s: %#v", call.Method, params)
+		}
+		return []map[string]interface{}{{
+			"eventid": "100", "source": "0", "object": "0", "objectid": "13", "clock": "1400000000", "value": "1", "acknowledged": "1",
+			"acknowledges": []map[string]string{
+				{"acknowledgeid": "2", "userid": "1", "eventid": "100", "clock": "1400000900", "message": "Fixed disk", "action": "5"},
+				{"acknowledgeid": "1", "userid": "3", "eventid": "100", "clock": "1400000300", "message": "Looking", "action": "6"},
+			},
+		}}
+	})
+	defer server.Close()
+
+	events, err := api.EventsGetWithAcks([]string{"100"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || len(events[0].Acknowledges) != 2 {
+		t.Fatalf("Unexpected events: %#v", events)
+	}
+	first, second := events[0].Acknowledges[0], events[0].Acknowledges[1]
+	if first.AcknowledgeId != "1" || first.Message != "Looking" || !first.Has(AcknowledgeEvent) || first.Has(CloseProblemAction) {
+		t.Errorf("Unexpected first acknowledge: %#v", first)
+	}
+	if second.AcknowledgeId != "2" || !second.Has(CloseProblemAction) || !second.Has(AddMessageAction) {
+		t.Errorf("Unexpected second acknowledge: %#v", second)
+	}
+	if clock, err := second.Time(); err != nil || !clock.Equal(time.Unix(1400000900, 0)) {
+		t.Errorf("Unexpected clock: %v (%v)", clock, err)
+	}
+	_, err = api.EventsGetWithAcks(nil)
+	if err != ErrEmptyIds {
+		t.Errorf("Expected ErrEmptyIds, got %v", err)
+	}
+}
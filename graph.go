@@ -0,0 +1,95 @@
+package zabbix
+
+import (
+	"fmt"
+)
+
+type (
+	GraphAxisType int
+)
+
+const (
+	CalculatedAxis GraphAxisType = 0
+	FixedAxis      GraphAxisType = 1
+	ItemAxis       GraphAxisType = 2
+)
+
+// https://www.zabbix.com/documentation/3.0/manual/api/reference/graphitem/object
+type GraphItem struct {
+	GItemId string `json:"gitemid,omitempty"`
+	ItemId  string `json:"itemid"`
+	Color   string `json:"color"` // hex, like "00AA00"
+}
+
+type GraphItems []GraphItem
+
+// https://www.zabbix.com/documentation/3.0/manual/api/reference/graph/object
+type Graph struct {
+	GraphId string `json:"graphid,omitempty"`
+	Name    string `json:"name"`
+	Width   int    `json:"width,string"`
+	Height  int    `json:"height,string"`
+
+	// Y axis limits. Ymin and Ymax are used by FixedAxis, YminItemId and YmaxItemId by ItemAxis:
+	// axis is limited by last value of that item.
+	YminType   GraphAxisType `json:"ymin_type,string"`
+	YmaxType   GraphAxisType `json:"ymax_type,string"`
+	Ymin       float64       `json:"yaxismin,string"`
+	Ymax       float64       `json:"yaxismax,string"`
+	YminItemId string        `json:"ymin_itemid,omitempty"`
+	YmaxItemId string        `json:"ymax_itemid,omitempty"`
+
+	// 0 or 1, nil keeps Zabbix default 1.
+	ShowLegend     *int `json:"show_legend,omitempty,string"`
+	ShowWorkPeriod *int `json:"show_work_period,omitempty,string"`
+
+	// Items are required on create. Returned by selectGraphItems query parameter.
+	Items GraphItems `json:"gitems,omitempty"`
+}
+
+type Graphs []Graph
+
+// Checks that item-based axes have items.
+func (graphs Graphs) validate() error {
+	for _, graph := range graphs {
+		switch {
+		case graph.YminType == ItemAxis && (graph.YminItemId == "" || graph.YminItemId == "0"):
+			return fmt.Errorf("Graph %s should have Y axis minimum item.", graph.Name)
+		case graph.YmaxType == ItemAxis && (graph.YmaxItemId == "" || graph.YmaxItemId == "0"):
+			return fmt.Errorf("Graph %s should have Y axis maximum item.", graph.Name)
+		}
+	}
+	return nil
+}
+
+// Wrapper for graph.get: https://www.zabbix.com/documentation/3.0/manual/api/reference/graph/get
+func (api *API) GraphsGet(params Params) (res Graphs, err error) {
+	params = params.Clone()
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	err = api.callInto("graph.get", params, &res)
+	return
+}
+
+// Wrapper for graph.create: https://www.zabbix.com/documentation/3.0/manual/api/reference/graph/create
+func (api *API) GraphsCreate(graphs Graphs) (err error) {
+	err = graphs.validate()
+	if err != nil {
+		return
+	}
+
+	response, err := api.CallWithError("graph.create", graphs)
+	if err != nil {
+		return
+	}
+
+	graphids, err := response.ResultIDs("graphids")
+	if err != nil {
+		return
+	}
+	for i, id := range graphids {
+		graphs[i].GraphId = id
+	}
+	return
+}
@@ -0,0 +1,53 @@
+package zabbix_test
+
+import (
+	"testing"
+
+	. "."
+)
+
+func TestGraphsCreate(t *testing.T) {
+	var created []map[string]interface{}
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		if call.Method != "graph.create" {
+			t.Errorf("Unexpected method %s", call.Method)
+			return nil
+		}
+		call.decodeParams(&created, t)
+		return map[string]interface{}{"graphids": []string{"700", "701"}}
+	})
+	defer server.Close()
+
+	items := GraphItems{{ItemId: "23", Color: "00AA00"}}
+	hide := 0
+	graphs := Graphs{
+		{Name: "CPU load", Width: 900, Height: 200, YminType: FixedAxis, Ymin: 0, YmaxType: FixedAxis, Ymax: 100, ShowLegend: &hide, Items: items},
+		{Name: "Disk usage", Width: 900, Height: 200, YmaxType: ItemAxis, YmaxItemId: "24", Items: items},
+	}
+	err := api.GraphsCreate(graphs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if graphs[0].GraphId != "700" || graphs[1].GraphId != "701" {
+		t.Errorf("Unexpected ids: %#v", graphs)
+	}
+
+	if len(created) != 2 {
+		t.Fatalf("Unexpected params: %#v", created)
+	}
+	fixed, item := created[0], created[1]
+	if fixed["ymin_type"] != "1" || fixed["yaxismax"] != "100" || fixed["show_legend"] != "0" || fixed["ymax_itemid"] != nil {
+		t.Errorf("Unexpected fixed axis graph: %#v", fixed)
+	}
+	if item["ymax_type"] != "2" || item["ymax_itemid"] != "24" || item["ymin_type"] != "0" || item["show_legend"] != nil || item["show_work_period"] != nil {
+		t.Errorf("Unexpected item axis graph: %#v", item)
+	}
+	if gitems, ok := item["gitems"].([]interface{}); !ok || len(gitems) != 1 {
+		t.Errorf("Unexpected graph items: %#v", item["gitems"])
+	}
+
+	err = api.GraphsCreate(Graphs{{Name: "Broken", Width: 900, Height: 200, YminType: ItemAxis, Items: items}})
+	if err == nil {
+		t.Error("Expected error for item axis without item")
+	}
+}
@@ -0,0 +1,194 @@
+package zabbix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// https://www.zabbix.com/documentation/2.0/manual/appendix/api/history/definitions
+// Value is kept as string for all value types, use Float() or Uint() to convert numeric ones.
+type HistoryRecord struct {
+	ItemId string `json:"itemid"`
+	Clock  int64  `json:"clock"`
+	Ns     int    `json:"ns"`
+	Value  string `json:"value"`
+}
+
+type HistoryRecords []HistoryRecord
+
+// Accepts both strings and numbers for all fields: Zabbix versions and value types differ in that.
+func (r *HistoryRecord) UnmarshalJSON(b []byte) (err error) {
+	var raw struct {
+		ItemId json.RawMessage `json:"itemid"`
+		Clock  json.RawMessage `json:"clock"`
+		Ns     json.RawMessage `json:"ns"`
+		Value  json.RawMessage `json:"value"`
+	}
+	err = json.Unmarshal(b, &raw)
+	if err != nil {
+		return
+	}
+
+	var clock, ns string
+	for _, f := range []struct {
+		raw json.RawMessage
+		s   *string
+	}{{raw.ItemId, &r.ItemId}, {raw.Clock, &clock}, {raw.Ns, &ns}, {raw.Value, &r.Value}} {
+		*f.s, err = rawString(f.raw)
+		if err != nil {
+			return
+		}
+	}
+	if clock != "" {
+		r.Clock, err = strconv.ParseInt(clock, 10, 64)
+		if err != nil {
+			return fmt.Errorf("Failed to parse history clock %q.", clock)
+		}
+	}
+	if ns != "" {
+		r.Ns, err = strconv.Atoi(ns)
+		if err != nil {
+			return fmt.Errorf("Failed to parse history ns %q.", ns)
+		}
+	}
+	return
+}
+
+// Returns JSON string as is and JSON number as its text, empty string for missing value or null.
+func rawString(b json.RawMessage) (s string, err error) {
+	if len(b) == 0 || string(b) == "null" {
+		return
+	}
+	if b[0] == '"' {
+		err = json.Unmarshal(b, &s)
+		return
+	}
+	var n json.Number
+	err = json.Unmarshal(b, &n)
+	s = string(n)
+	return
+}
+
+// Returns value of Float history record.
+func (r HistoryRecord) Float() (float64, error) {
+	return strconv.ParseFloat(r.Value, 64)
+}
+
+// Returns value of Unsigned history record.
+func (r HistoryRecord) Uint() (uint64, error) {
+	return strconv.ParseUint(r.Value, 10, 64)
+}
+
+// Returns time of record.
+func (r HistoryRecord) Time() time.Time {
+	return time.Unix(r.Clock, int64(r.Ns))
+}
+
+// Wrapper for history.get: https://www.zabbix.com/documentation/2.0/manual/appendix/api/history/get
+func (api *API) HistoryGet(params Params) (res HistoryRecords, err error) {
+	params = params.Clone()
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	err = api.callInto("history.get", params, &res)
+	return
+}
+
+// Gets up to n latest history values of item, newest first.
+// Fewer values are returned if older ones are already removed by housekeeper.
+func (api *API) HistoryGetLastN(itemId string, valueType ValueType, n int) (res HistoryRecords, err error) {
+	return api.HistoryGet(Params{
+		"history":   valueType,
+		"itemids":   itemId,
+		"sortfield": "clock",
+		"sortorder": "DESC",
+		"limit":     n,
+	})
+}
+
+// Calls fn for every history value of item between from and to (inclusive), oldest first.
+// Values are requested with one history.get call per window, so only one window is kept in memory.
+// Walk stops with error returned by fn, or with ctx.Err() if ctx is done.
+func (api *API) HistoryStream(ctx context.Context, itemId string, valueType ValueType, from, to time.Time, window time.Duration,
+	fn func(HistoryRecord) error) (err error) {
+	step := int64(window / time.Second)
+	if step < 1 {
+		return fmt.Errorf("History window should be at least 1s, got %s.", window)
+	}
+
+	for start, till := from.Unix(), to.Unix(); start <= till; start += step {
+		end := start + step - 1
+		if end > till {
+			end = till
+		}
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		var records HistoryRecords
+		records, err = api.HistoryGet(Params{
+			"history":   valueType,
+			"itemids":   itemId,
+			"time_from": start,
+			"time_till": end,
+			"sortfield": "clock",
+			"sortorder": "ASC",
+		})
+		if err != nil {
+			return
+		}
+		for _, r := range records {
+			if err = ctx.Err(); err != nil {
+				return
+			}
+			if err = fn(r); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// Counts history values of item between from and to with countOutput option of history.get:
+// https://www.zabbix.com/documentation/2.0/manual/appendix/api/history/get
+// History is stored in per-value type tables, so item's value type is checked first:
+// history.get silently returns zero for wrong one.
+func (api *API) HistoryCount(itemId string, valueType ValueType, from, to time.Time) (count int, err error) {
+	items, err := api.ItemsGet(Params{"itemids": itemId, "output": []string{"itemid", "value_type"}})
+	if err != nil {
+		return
+	}
+	if len(items) != 1 {
+		e := ExpectedOneResult(len(items))
+		err = &e
+		return
+	}
+	if items[0].ValueType != strconv.Itoa(int(valueType)) {
+		err = fmt.Errorf("Item %s has value type %s, not %d.", itemId, items[0].ValueType, valueType)
+		return
+	}
+
+	response, err := api.CallWithError("history.get", Params{
+		"history":     valueType,
+		"itemids":     itemId,
+		"time_from":   from.Unix(),
+		"time_till":   to.Unix(),
+		"countOutput": true,
+	})
+	if err != nil {
+		return
+	}
+
+	switch result := response.Result.(type) {
+	case string:
+		count, err = strconv.Atoi(result)
+	case float64:
+		count = int(result)
+	default:
+		err = fmt.Errorf("Expected count in result, got %#v.", response.Result)
+	}
+	return
+}
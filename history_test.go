@@ -0,0 +1,188 @@
+package zabbix_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	. "."
+)
+
+func TestHistoryCount(t *testing.T) {
+	from := time.Unix(1400000000, 0)
+	to := from.Add(time.Hour)
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		var params map[string]interface{}
+		call.decodeParams(&params, t)
+		switch call.Method {
+		case "item.get":
+			return []map[string]string{{"itemid": "23", "value_type": "3"}}
+		case "history.get":
+			if params["history"] != float64(3) || params["itemids"] != "23" || params["countOutput"] != true {
+				t.Errorf("Unexpected params: %#v", params)
+			}
+			if params["time_from"] != float64(from.Unix()) || params["time_till"] != float64(to.Unix()) {
+				t.Errorf("Unexpected params: %#v", params)
+			}
+			return "42"
+		}
+		t.Errorf("Unexpected method %s", call.Method)
+		return nil
+	})
+	defer server.Close()
+
+	count, err := api.HistoryCount("23", Unsigned, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 42 {
+		t.Errorf("Expected 42, got %d", count)
+	}
+
+	_, err = api.HistoryCount("23", Float, from, to)
+	if err == nil {
+		t.Error("Expected error for wrong value type")
+	}
+}
+
+func TestHistoryGetLastN(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		if call.Method != "history.get" {
+			t.Errorf("Unexpected method %s", call.Method)
+			return nil
+		}
+		var params map[string]interface{}
+		call.decodeParams(&params, t)
+		if params["history"] != float64(0) || params["itemids"] != "23" || params["limit"] != float64(3) {
+			t.Errorf("Unexpected params: %#v", params)
+		}
+		if params["sortfield"] != "clock" || params["sortorder"] != "DESC" || params["output"] != "extend" {
+			t.Errorf("Unexpected params: %#v", params)
+		}
+		// only two values are left after housekeeping
+		return []map[string]string{
+			{"itemid": "23", "clock": "1400000120", "ns": "500", "value": "0.25"},
+			{"itemid": "23", "clock": "1400000060", "ns": "0", "value": "0.5"},
+		}
+	})
+	defer server.Close()
+
+	records, err := api.HistoryGetLastN("23", Float, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %#v", records)
+	}
+	if !records[0].Time().After(records[1].Time()) || records[0].Value != "0.25" {
+		t.Errorf("Expected newest record first, got %#v", records)
+	}
+	if !records[0].Time().Equal(time.Unix(1400000120, 500)) {
+		t.Errorf("Unexpected time %s", records[0].Time())
+	}
+}
+
+func TestHistoryRecordUnmarshal(t *testing.T) {
+	for _, payload := range []string{
+		`{"itemid": "23", "clock": "1400000000", "ns": "42", "value": "1.5"}`,
+		`{"itemid": 23, "clock": 1400000000, "ns": 42, "value": 1.5}`,
+	} {
+		var r HistoryRecord
+		if err := json.Unmarshal([]byte(payload), &r); err != nil {
+			t.Fatalf("%s: %s", payload, err)
+		}
+		expected := HistoryRecord{ItemId: "23", Clock: 1400000000, Ns: 42, Value: "1.5"}
+		if r != expected {
+			t.Errorf("%s: expected %#v, got %#v", payload, expected, r)
+		}
+		f, err := r.Float()
+		if err != nil || f != 1.5 {
+			t.Errorf("%s: expected 1.5, got %v (%v)", payload, f, err)
+		}
+	}
+
+	// text values stay strings, even if they look like numbers
+	var records HistoryRecords
+	err := json.Unmarshal([]byte(`[{"itemid": "24", "clock": "1400000000", "ns": "0", "value": "007"}, {"itemid": "24", "clock": 1400000001, "value": "error: disk full"}]`), &records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Value != "007" || records[1].Value != "error: disk full" || records[1].Ns != 0 {
+		t.Errorf("Unexpected records: %#v", records)
+	}
+	if _, err = records[1].Uint(); err == nil {
+		t.Error("Expected error for text value")
+	}
+
+	if err = json.Unmarshal([]byte(`{"clock": "yesterday"}`), new(HistoryRecord)); err == nil {
+		t.Error("Expected error for bad clock")
+	}
+}
+
+func TestHistoryStream(t *testing.T) {
+	from := time.Unix(1400000000, 0)
+	var windows [][2]float64
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		var params map[string]interface{}
+		call.decodeParams(&params, t)
+		if call.Method != "history.get" || params["itemids"] != "23" || params["history"] != float64(3) || params["sortorder"] != "ASC" {
+			t.Errorf("Unexpected call %s: %#v", call.Method, params)
+		}
+		start, end := params["time_from"].(float64), params["time_till"].(float64)
+		windows = append(windows, [2]float64{start, end})
+		return []map[string]string{
+			{"itemid": "23", "clock": strconv.Itoa(int(start)), "value": "1"},
+			{"itemid": "23", "clock": strconv.Itoa(int(start) + 60), "value": "2"},
+			{"itemid": "23", "clock": strconv.Itoa(int(start) + 120), "value": "3"},
+		}
+	})
+	defer server.Close()
+
+	var calls int
+	err := api.HistoryStream(context.Background(), "23", Unsigned, from, from.Add(2*time.Hour-time.Second), time.Hour, func(r HistoryRecord) error {
+		calls++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 6 {
+		t.Errorf("Expected 6 callback calls, got %d", calls)
+	}
+	expected := [][2]float64{{1400000000, 1400003599}, {1400003600, 1400007199}}
+	if !reflect.DeepEqual(windows, expected) {
+		t.Errorf("Unexpected windows: %v", windows)
+	}
+
+	// callback error stops walk in the middle of first window
+	windows, calls = nil, 0
+	stop := errors.New("stop")
+	err = api.HistoryStream(context.Background(), "23", Unsigned, from, from.Add(2*time.Hour), time.Hour, func(r HistoryRecord) error {
+		calls++
+		if calls == 2 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || calls != 2 || len(windows) != 1 {
+		t.Errorf("Unexpected result: %v, %d calls, %d windows", err, calls, len(windows))
+	}
+
+	// cancelled context stops walk before next window
+	windows, calls = nil, 0
+	ctx, cancel := context.WithCancel(context.Background())
+	err = api.HistoryStream(ctx, "23", Unsigned, from, from.Add(2*time.Hour), time.Hour, func(r HistoryRecord) error {
+		calls++
+		if calls == 3 {
+			cancel()
+		}
+		return nil
+	})
+	if err != context.Canceled || calls != 3 || len(windows) != 1 {
+		t.Errorf("Unexpected result: %v, %d calls, %d windows", err, calls, len(windows))
+	}
+}
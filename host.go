@@ -1,22 +1,64 @@
 package zabbix
 
 import (
+	"fmt"
+	"regexp"
+	"strconv"
+
 	"github.com/AlekSi/reflector"
 )
 
 type (
-	AvailableType int
-	StatusType    int
+	AvailableType     int
+	InventoryMode     int
+	MaintenanceStatus int
+
+	// Agent connections encryption. Host accepts bitmask of them, but connects with one.
+	TLSMode int
+
+	// Host status: monitored host is enabled one, unmonitored is disabled.
+	StatusType = Status
 )
 
 const (
 	Available   AvailableType = 1
 	Unavailable AvailableType = 2
 
-	Monitored   StatusType = 0
-	Unmonitored StatusType = 1
+	Monitored   = Enabled
+	Unmonitored = Disabled
+
+	InventoryDisabled  InventoryMode = -1
+	InventoryManual    InventoryMode = 0
+	InventoryAutomatic InventoryMode = 1
+
+	NoMaintenance MaintenanceStatus = 0
+	InEffect      MaintenanceStatus = 1
+
+	NoEncryption TLSMode = 1
+	PSK          TLSMode = 2
+	Certificate  TLSMode = 4
 )
 
+// Common fields of https://www.zabbix.com/documentation/3.0/manual/api/reference/host/object#host_inventory
+type HostInventory struct {
+	Name        string `json:"name,omitempty"`
+	Alias       string `json:"alias,omitempty"`
+	Type        string `json:"type,omitempty"`
+	OS          string `json:"os,omitempty"`
+	Hardware    string `json:"hardware,omitempty"`
+	Software    string `json:"software,omitempty"`
+	Vendor      string `json:"vendor,omitempty"`
+	Model       string `json:"model,omitempty"`
+	SerialNoA   string `json:"serialno_a,omitempty"`
+	AssetTag    string `json:"asset_tag,omitempty"`
+	MACAddressA string `json:"macaddress_a,omitempty"`
+	Location    string `json:"location,omitempty"`
+	LocationLat string `json:"location_lat,omitempty"`
+	LocationLon string `json:"location_lon,omitempty"`
+	Contact     string `json:"contact,omitempty"`
+	Notes       string `json:"notes,omitempty"`
+}
+
 // https://www.zabbix.com/documentation/2.0/manual/appendix/api/host/definitions
 type Host struct {
 	HostId    string        `json:"hostid,omitempty"`
@@ -26,15 +68,45 @@ type Host struct {
 	Name      string        `json:"name"`
 	Status    StatusType    `json:"status"`
 
+	// Free text, may be multi-line (Zabbix 4.0+). Nil description is not sent, pointer to empty string
+	// clears it on update. HostsGet fills only non-empty descriptions.
+	Description *string `json:"description,omitempty"`
+
+	// Returned by selectInventory query parameter. Inventory is saved only if InventoryMode is not disabled,
+	// HostsCreate and HostsUpdate set it to manual if inventory is given without mode.
+	Inventory     *HostInventory `json:"inventory,omitempty"`
+	InventoryMode *InventoryMode `json:"inventory_mode,omitempty"`
+
+	// Returned by selectMacros query parameter. Also may be used to create host with macros.
+	Macros UserMacros `json:"macros,omitempty"`
+
+	// Templates linked to host, returned by selectParentTemplates query parameter. Not sent on create or update.
+	ParentTemplates Templates `json:"-"`
+
+	// Encryption of connections to and from agent (Zabbix 3.0+). PSK fields are required for PSK encryption,
+	// TLSPSK is write-only and never logged. TLSIssuer and TLSSubject optionally restrict certificates.
+	TLSConnect     TLSMode `json:"tls_connect,omitempty"`
+	TLSAccept      TLSMode `json:"tls_accept,omitempty"`
+	TLSPSKIdentity string  `json:"tls_psk_identity,omitempty"`
+	TLSPSK         string  `json:"tls_psk,omitempty"`
+	TLSIssuer      string  `json:"tls_issuer,omitempty"`
+	TLSSubject     string  `json:"tls_subject,omitempty"`
+
+	// Read-only maintenance status and Id of maintenance in effect ("0" if none). Not sent on create or update.
+	MaintenanceStatus MaintenanceStatus `json:"-"`
+	MaintenanceId     string            `json:"-"`
+
 	// Fields below used only when creating hosts
-	GroupIds   HostGroupIds   `json:"groups,omitempty"`
-	Interfaces HostInterfaces `json:"interfaces,omitempty"`
+	GroupIds    HostGroupIds   `json:"groups,omitempty"`
+	Interfaces  HostInterfaces `json:"interfaces,omitempty"`
+	TemplateIds TemplateIds    `json:"templates,omitempty"`
 }
 
 type Hosts []Host
 
 // Wrapper for host.get: https://www.zabbix.com/documentation/2.0/manual/appendix/api/host/get
 func (api *API) HostsGet(params Params) (res Hosts, err error) {
+	params = params.Clone()
 	if _, present := params["output"]; !present {
 		params["output"] = "extend"
 	}
@@ -43,12 +115,95 @@ func (api *API) HostsGet(params Params) (res Hosts, err error) {
 		return
 	}
 
-	reflector.MapsToStructs2(response.Result.([]interface{}), &res, reflector.Strconv, "json")
+	results := response.Result.([]interface{})
+	reflector.MapsToStructs2(results, &res, reflector.Strconv, "json")
+	for i, result := range results {
+		m := result.(map[string]interface{})
+		res[i].fillInventory(m)
+		res[i].fillParentTemplates(m)
+		res[i].fillMacros(m)
+		res[i].fillMaintenance(m)
+		res[i].fillDescription(m)
+	}
 	return
 }
 
-// Gets hosts by host group Ids.
+// Fills inventory fields which are not handled by reflector.
+func (host *Host) fillInventory(m map[string]interface{}) {
+	// inventory is empty array if it's disabled
+	if inventory, ok := m["inventory"].(map[string]interface{}); ok {
+		host.Inventory = new(HostInventory)
+		reflector.MapToStruct(inventory, host.Inventory, reflector.Strconv, "json")
+	}
+	if s, ok := m["inventory_mode"].(string); ok {
+		if mode, err := strconv.Atoi(s); err == nil {
+			host.InventoryMode = new(InventoryMode)
+			*host.InventoryMode = InventoryMode(mode)
+		}
+	}
+}
+
+// Fills description which is not handled by reflector.
+func (host *Host) fillDescription(m map[string]interface{}) {
+	if s, ok := m["description"].(string); ok && s != "" {
+		host.Description = &s
+	}
+}
+
+// Fills parent templates which are not handled by reflector.
+func (host *Host) fillParentTemplates(m map[string]interface{}) {
+	if templates, ok := m["parentTemplates"].([]interface{}); ok {
+		host.ParentTemplates = Templates{}
+		reflector.MapsToStructs2(templates, &host.ParentTemplates, reflector.Strconv, "json")
+	}
+}
+
+// Fills macros which are not handled by reflector.
+func (host *Host) fillMacros(m map[string]interface{}) {
+	if macros, ok := m["macros"].([]interface{}); ok {
+		host.Macros = UserMacros{}
+		reflector.MapsToStructs2(macros, &host.Macros, reflector.Strconv, "json")
+	}
+}
+
+// Fills read-only maintenance fields.
+func (host *Host) fillMaintenance(m map[string]interface{}) {
+	if s, ok := m["maintenance_status"].(string); ok {
+		if status, err := strconv.Atoi(s); err == nil {
+			host.MaintenanceStatus = MaintenanceStatus(status)
+		}
+	}
+	host.MaintenanceId, _ = m["maintenanceid"].(string)
+}
+
+// Sets manual inventory mode for hosts with inventory, but without mode.
+func (hosts Hosts) setInventoryMode() {
+	for i := range hosts {
+		if hosts[i].Inventory != nil && hosts[i].InventoryMode == nil {
+			mode := InventoryManual
+			hosts[i].InventoryMode = &mode
+		}
+	}
+}
+
+// Gets hosts with their macros.
+func (api *API) HostsGetWithMacros(params Params) (res Hosts, err error) {
+	params = params.Clone()
+	params["selectMacros"] = "extend"
+	return api.HostsGet(params)
+}
+
+// Gets hosts with maintenance in effect.
+func (api *API) HostsGetInMaintenance() (res Hosts, err error) {
+	return api.HostsGet(Params{"filter": Params{"maintenance_status": InEffect}})
+}
+
+// Gets hosts by host group Ids. Returns ErrEmptyIds if ids is empty.
 func (api *API) HostsGetByHostGroupIds(ids []string) (res Hosts, err error) {
+	if len(ids) == 0 {
+		err = ErrEmptyIds
+		return
+	}
 	return api.HostsGet(Params{"groupids": ids})
 }
 
@@ -93,17 +248,102 @@ func (api *API) HostGetByHost(host string) (res *Host, err error) {
 	return
 }
 
+// Checks encryption fields. PSK is required for PSK encryption on create, but it's not returned by host.get,
+// so on update PSK fields are checked only if one of them is given.
+func (hosts Hosts) validateTLS(create bool) error {
+	for _, host := range hosts {
+		switch host.TLSConnect {
+		case 0, NoEncryption, PSK, Certificate:
+		default:
+			return fmt.Errorf("Host %s should connect with a single encryption mode, got %d.", host.Host, host.TLSConnect)
+		}
+		if host.TLSAccept < 0 || host.TLSAccept > NoEncryption|PSK|Certificate {
+			return fmt.Errorf("Host %s has unknown accepted encryption modes %d.", host.Host, host.TLSAccept)
+		}
+
+		usesPSK := host.TLSConnect == PSK || host.TLSAccept&PSK != 0
+		givenPSK := host.TLSPSKIdentity != "" || host.TLSPSK != ""
+		if usesPSK && (create || givenPSK) {
+			if host.TLSPSKIdentity == "" {
+				return fmt.Errorf("Host %s should have PSK identity for PSK encryption.", host.Host)
+			}
+			if !validPSK.MatchString(host.TLSPSK) {
+				return fmt.Errorf("Host %s should have PSK of at least 32 hex digits for PSK encryption.", host.Host)
+			}
+		}
+	}
+	return nil
+}
+
+var validPSK = regexp.MustCompile(`^[0-9A-Fa-f]{32,512}$`)
+
 // Wrapper for host.create: https://www.zabbix.com/documentation/2.0/manual/appendix/api/host/create
 func (api *API) HostsCreate(hosts Hosts) (err error) {
+	err = hosts.validateTLS(true)
+	if err != nil {
+		return
+	}
+	hosts.setInventoryMode()
 	response, err := api.CallWithError("host.create", hosts)
 	if err != nil {
 		return
 	}
 
-	result := response.Result.(map[string]interface{})
-	hostids := result["hostids"].([]interface{})
+	hostids, err := response.ResultIDs("hostids")
+	if err != nil {
+		return
+	}
 	for i, id := range hostids {
-		hosts[i].HostId = id.(string)
+		hosts[i].HostId = id
+	}
+	return
+}
+
+// Creates host linked to templates and returns it with ParentTemplates.
+// All templates are linked by the same host.create call, so host is not created if any of them can't be linked,
+// for example because templates have items with the same key; returned *Error describes the conflict.
+func (api *API) HostCreateWithTemplates(host Host, templateIds []string) (res *Host, err error) {
+	host.TemplateIds = make(TemplateIds, len(templateIds))
+	for i, id := range templateIds {
+		host.TemplateIds[i].TemplateId = id
+	}
+	hosts := Hosts{host}
+	err = api.HostsCreate(hosts)
+	if err != nil {
+		return
+	}
+
+	hosts, err = api.HostsGet(Params{"hostids": hosts[0].HostId, "selectParentTemplates": []string{"templateid", "host", "name"}})
+	if err != nil {
+		return
+	}
+	if len(hosts) == 1 {
+		res = &hosts[0]
+	} else {
+		e := ExpectedOneResult(len(hosts))
+		err = &e
+	}
+	return
+}
+
+// Wrapper for host.update: https://www.zabbix.com/documentation/2.0/manual/appendix/api/host/update
+func (api *API) HostsUpdate(hosts Hosts) (err error) {
+	err = hosts.validateTLS(false)
+	if err != nil {
+		return
+	}
+	hosts.setInventoryMode()
+	response, err := api.CallWithError("host.update", hosts)
+	if err != nil {
+		return
+	}
+
+	hostids, err := response.ResultIDs("hostids")
+	if err != nil {
+		return
+	}
+	if len(hosts) != len(hostids) {
+		err = &ExpectedMore{len(hosts), len(hostids)}
 	}
 	return
 }
@@ -133,8 +373,10 @@ func (api *API) HostsDeleteByIds(ids []string) (err error) {
 		return
 	}
 
-	result := response.Result.(map[string]interface{})
-	hostids := result["hostids"].([]interface{})
+	hostids, err := response.ResultIDs("hostids")
+	if err != nil {
+		return
+	}
 	if len(ids) != len(hostids) {
 		err = &ExpectedMore{len(ids), len(hostids)}
 	}
@@ -0,0 +1,76 @@
+package zabbix
+
+import (
+	"strconv"
+)
+
+// Availability of monitored hosts of host group. Unmonitored hosts are counted only in Disabled.
+type AvailabilitySummary struct {
+	Available   int
+	Unavailable int
+	Unknown     int
+	Disabled    int
+}
+
+// Returns number of monitored hosts.
+func (s AvailabilitySummary) Monitored() int {
+	return s.Available + s.Unavailable + s.Unknown
+}
+
+// Returns percent of available hosts among monitored ones, 0 if there are none.
+func (s AvailabilitySummary) Percent() float64 {
+	if s.Monitored() == 0 {
+		return 0
+	}
+	return float64(s.Available) * 100 / float64(s.Monitored())
+}
+
+// Counts available, unavailable and unknown monitored hosts of host group.
+// Zabbix 5.4 moved availability from hosts to their interfaces, so for it and later versions host is available
+// if any of its interfaces is available, unavailable if any is unavailable and none is available, and unknown otherwise.
+func (api *API) HostGroupAvailability(groupId string) (res AvailabilitySummary, err error) {
+	v, err := api.serverVersion()
+	if err != nil {
+		return
+	}
+
+	params := Params{"groupids": groupId, "output": []string{"hostid", "status", "available"}}
+	if v.atLeast(5, 4) {
+		params["output"] = []string{"hostid", "status"}
+		params["selectInterfaces"] = []string{"available"}
+	}
+	var hosts []struct {
+		Status     string `json:"status"`
+		Available  string `json:"available"`
+		Interfaces []struct {
+			Available string `json:"available"`
+		} `json:"interfaces"`
+	}
+	err = api.callInto("host.get", params, &hosts)
+	if err != nil {
+		return
+	}
+
+	for _, host := range hosts {
+		if host.Status != strconv.Itoa(int(Monitored)) {
+			res.Disabled++
+			continue
+		}
+
+		available := host.Available
+		for _, iface := range host.Interfaces {
+			if available == "" || available == "0" || iface.Available == strconv.Itoa(int(Available)) {
+				available = iface.Available
+			}
+		}
+		switch available {
+		case strconv.Itoa(int(Available)):
+			res.Available++
+		case strconv.Itoa(int(Unavailable)):
+			res.Unavailable++
+		default:
+			res.Unknown++
+		}
+	}
+	return
+}
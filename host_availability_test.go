@@ -0,0 +1,56 @@
+package zabbix_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "."
+)
+
+func TestHostGroupAvailability(t *testing.T) {
+	for version, hosts := range map[string]string{
+		"5.0.10": `[
+			{"hostid": "1", "status": "0", "available": "1"},
+			{"hostid": "2", "status": "0", "available": "1"},
+			{"hostid": "3", "status": "0", "available": "2"},
+			{"hostid": "4", "status": "0", "available": "0"},
+			{"hostid": "5", "status": "1", "available": "2"}
+		]`,
+		"6.0.12": `[
+			{"hostid": "1", "status": "0", "interfaces": [{"available": "1"}]},
+			{"hostid": "2", "status": "0", "interfaces": [{"available": "2"}, {"available": "1"}]},
+			{"hostid": "3", "status": "0", "interfaces": [{"available": "2"}, {"available": "0"}]},
+			{"hostid": "4", "status": "0", "interfaces": []},
+			{"hostid": "5", "status": "1", "interfaces": [{"available": "2"}]}
+		]`,
+	} {
+		api, server := newMockAPI(t, func(call *mockCall) interface{} {
+			switch call.Method {
+			case "apiinfo.version", "APIInfo.version":
+				return version
+			case "host.get":
+				var params map[string]interface{}
+				call.decodeParams(&params, t)
+				if params["groupids"] != "2" || (params["selectInterfaces"] != nil) != (version == "6.0.12") {
+					t.Errorf("%s: unexpected params: %#v", version, params)
+				}
+				return json.RawMessage(hosts)
+			}
+			t.Errorf("Unexpected method %s", call.Method)
+			return nil
+		})
+
+		summary, err := api.HostGroupAvailability("2")
+		server.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := AvailabilitySummary{Available: 2, Unavailable: 1, Unknown: 1, Disabled: 1}
+		if summary != expected {
+			t.Errorf("%s: unexpected summary %#v", version, summary)
+		}
+		if summary.Percent() != 50 {
+			t.Errorf("%s: expected 50%%, got %v", version, summary.Percent())
+		}
+	}
+}
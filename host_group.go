@@ -1,6 +1,8 @@
 package zabbix
 
 import (
+	"fmt"
+
 	"github.com/AlekSi/reflector"
 )
 
@@ -30,6 +32,7 @@ type HostGroupIds []HostGroupId
 
 // Wrapper for hostgroup.get: https://www.zabbix.com/documentation/2.0/manual/appendix/api/hostgroup/get
 func (api *API) HostGroupsGet(params Params) (res HostGroups, err error) {
+	params = params.Clone()
 	if _, present := params["output"]; !present {
 		params["output"] = "extend"
 	}
@@ -65,10 +68,62 @@ func (api *API) HostGroupsCreate(hostGroups HostGroups) (err error) {
 		return
 	}
 
-	result := response.Result.(map[string]interface{})
-	groupids := result["groupids"].([]interface{})
+	groupids, err := response.ResultIDs("groupids")
+	if err != nil {
+		return
+	}
 	for i, id := range groupids {
-		hostGroups[i].GroupId = id.(string)
+		hostGroups[i].GroupId = id
+	}
+	return
+}
+
+// Gets Ids of host groups with given names, creating missing groups. Ids are in the same order as names.
+// Group created concurrently by someone else between get and create is fetched again.
+func (api *API) HostGroupsEnsure(names []string) (ids []string, err error) {
+	existing, err := api.hostGroupIdsByName(names)
+	if err != nil {
+		return
+	}
+
+	ids = make([]string, len(names))
+	for i, name := range names {
+		id, ok := existing[name]
+		if !ok {
+			groups := HostGroups{{Name: name}}
+			err = api.HostGroupsCreate(groups)
+			switch {
+			case err == nil:
+				id = groups[0].GroupId
+			case isAlreadyExists(err):
+				var created map[string]string
+				created, err = api.hostGroupIdsByName([]string{name})
+				if err != nil {
+					return nil, err
+				}
+				if id, ok = created[name]; !ok {
+					return nil, fmt.Errorf("Host group %s already exists, but it's not found.", name)
+				}
+			default:
+				return nil, err
+			}
+			existing[name] = id
+		}
+		ids[i] = id
+	}
+	return
+}
+
+// Returns map of names to Ids of existing host groups.
+func (api *API) hostGroupIdsByName(names []string) (res map[string]string, err error) {
+	groups, err := api.HostGroupsGet(Params{"filter": map[string]interface{}{"name": names}, "output": []string{"groupid", "name"}})
+	if err != nil {
+		return
+	}
+
+	res = make(map[string]string, len(groups))
+	for _, group := range groups {
+		res[group.Name] = group.GroupId
 	}
 	return
 }
@@ -97,8 +152,10 @@ func (api *API) HostGroupsDeleteByIds(ids []string) (err error) {
 		return
 	}
 
-	result := response.Result.(map[string]interface{})
-	groupids := result["groupids"].([]interface{})
+	groupids, err := response.ResultIDs("groupids")
+	if err != nil {
+		return
+	}
 	if len(ids) != len(groupids) {
 		err = &ExpectedMore{len(ids), len(groupids)}
 	}
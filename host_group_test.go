@@ -63,3 +63,59 @@ func TestHostGroups(t *testing.T) {
 		t.Errorf("Error deleting group.\nOld groups: %#v\nNew groups: %#v", groups, groups2)
 	}
 }
+
+func TestHostGroupsEnsure(t *testing.T) {
+	var created []string
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		switch call.Method {
+		case "hostgroup.get":
+			var params struct {
+				Filter struct {
+					Name []string `json:"name"`
+				} `json:"filter"`
+			}
+			call.decodeParams(&params, t)
+			res := []map[string]string{}
+			for _, name := range params.Filter.Name {
+				switch name {
+				case "Linux servers":
+					res = append(res, map[string]string{"groupid": "2", "name": name})
+				case "Race":
+					// created by someone else after first get
+					if len(created) != 0 {
+						res = append(res, map[string]string{"groupid": "43", "name": name})
+					}
+				}
+			}
+			return res
+		case "hostgroup.create":
+			var groups []map[string]string
+			call.decodeParams(&groups, t)
+			created = append(created, groups[0]["name"])
+			if groups[0]["name"] == "Race" {
+				return &Error{Code: -32602, Message: "Invalid params.", Data: `Host group "Race" already exists.`}
+			}
+			return map[string]interface{}{"groupids": []string{"42"}}
+		}
+		t.Errorf("Unexpected method %s", call.Method)
+		return nil
+	})
+	defer server.Close()
+
+	ids, err := api.HostGroupsEnsure([]string{"Linux servers", "New"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ids, []string{"2", "42"}) || !reflect.DeepEqual(created, []string{"New"}) {
+		t.Errorf("Unexpected ids %v, created %v", ids, created)
+	}
+
+	created = nil
+	ids, err = api.HostGroupsEnsure([]string{"Race"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ids, []string{"43"}) {
+		t.Errorf("Unexpected ids %v", ids)
+	}
+}
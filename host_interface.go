@@ -1,5 +1,11 @@
 package zabbix
 
+import (
+	"fmt"
+
+	"github.com/AlekSi/reflector"
+)
+
 type (
 	InterfaceType int
 )
@@ -13,12 +19,72 @@ const (
 
 // https://www.zabbix.com/documentation/2.0/manual/appendix/api/hostinterface/definitions
 type HostInterface struct {
-	DNS   string        `json:"dns"`
-	IP    string        `json:"ip"`
-	Main  int           `json:"main"`
-	Port  string        `json:"port"`
-	Type  InterfaceType `json:"type"`
-	UseIP int           `json:"useip"`
+	InterfaceId string        `json:"interfaceid,omitempty"`
+	HostId      string        `json:"hostid,omitempty"`
+	DNS         string        `json:"dns"`
+	IP          string        `json:"ip"`
+	Main        int           `json:"main"`
+	Port        string        `json:"port"`
+	Type        InterfaceType `json:"type"`
+	UseIP       int           `json:"useip"`
 }
 
 type HostInterfaces []HostInterface
+
+// Wrapper for hostinterface.get: https://www.zabbix.com/documentation/2.0/manual/appendix/api/hostinterface/get
+func (api *API) HostInterfacesGet(params Params) (res HostInterfaces, err error) {
+	params = params.Clone()
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("hostinterface.get", params)
+	if err != nil {
+		return
+	}
+
+	reflector.MapsToStructs2(response.Result.([]interface{}), &res, reflector.Strconv, "json")
+	return
+}
+
+// Makes interface main one among host interfaces of the same type, making current main interface non-main.
+// Both changes are sent in one hostinterface.update call, demoting first: Zabbix rejects host with two main
+// interfaces of the same type as well as host without main one. Does nothing if interface is already main.
+func (api *API) SetMainInterface(hostId, interfaceId string) (err error) {
+	ifaces, err := api.HostInterfacesGet(Params{"hostids": hostId, "output": []string{"interfaceid", "main", "type"}})
+	if err != nil {
+		return
+	}
+
+	var target *HostInterface
+	for i := range ifaces {
+		if ifaces[i].InterfaceId == interfaceId {
+			target = &ifaces[i]
+		}
+	}
+	if target == nil {
+		err = fmt.Errorf("Host %s has no interface %s.", hostId, interfaceId)
+		return
+	}
+	if target.Main == 1 {
+		return
+	}
+
+	var updates []Params
+	for _, iface := range ifaces {
+		if iface.Type == target.Type && iface.Main == 1 {
+			updates = append(updates, Params{"interfaceid": iface.InterfaceId, "main": 0})
+		}
+	}
+	updates = append(updates, Params{"interfaceid": interfaceId, "main": 1})
+
+	response, err := api.CallWithError("hostinterface.update", updates)
+	if err != nil {
+		return
+	}
+
+	interfaceids, err := response.ResultIDs("interfaceids")
+	if err == nil && len(interfaceids) != len(updates) {
+		err = &ExpectedMore{len(updates), len(interfaceids)}
+	}
+	return
+}
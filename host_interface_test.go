@@ -0,0 +1,71 @@
+package zabbix_test
+
+import (
+	"reflect"
+	"testing"
+)
+
+func hostInterfacesMock(t *testing.T, updates *[]map[string]interface{}) func(call *mockCall) interface{} {
+	return func(call *mockCall) interface{} {
+		switch call.Method {
+		case "hostinterface.get":
+			var params map[string]interface{}
+			call.decodeParams(&params, t)
+			if params["hostids"] != "10084" {
+				t.Errorf("Unexpected params: %#v", params)
+			}
+			return []map[string]string{
+				{"interfaceid": "30", "type": "1", "main": "1"},
+				{"interfaceid": "31", "type": "1", "main": "0"},
+				{"interfaceid": "32", "type": "2", "main": "1"},
+			}
+		case "hostinterface.update":
+			call.decodeParams(updates, t)
+			ids := []string{}
+			for _, u := range *updates {
+				ids = append(ids, u["interfaceid"].(string))
+			}
+			return map[string]interface{}{"interfaceids": ids}
+		}
+		t.Errorf("Unexpected method %s", call.Method)
+		return nil
+	}
+}
+
+func TestSetMainInterface(t *testing.T) {
+	var updates []map[string]interface{}
+	api, server := newMockAPI(t, hostInterfacesMock(t, &updates))
+	defer server.Close()
+
+	err := api.SetMainInterface("10084", "31")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []map[string]interface{}{
+		{"interfaceid": "30", "main": float64(0)},
+		{"interfaceid": "31", "main": float64(1)},
+	}
+	if !reflect.DeepEqual(updates, expected) {
+		t.Errorf("Unexpected updates: %#v", updates)
+	}
+}
+
+func TestSetMainInterfaceAlreadyMain(t *testing.T) {
+	var updates []map[string]interface{}
+	api, server := newMockAPI(t, hostInterfacesMock(t, &updates))
+	defer server.Close()
+
+	err := api.SetMainInterface("10084", "32")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updates != nil {
+		t.Errorf("Expected no updates, got %#v", updates)
+	}
+
+	err = api.SetMainInterface("10084", "33")
+	if err == nil {
+		t.Error("Expected error for unknown interface")
+	}
+}
@@ -0,0 +1,104 @@
+package zabbix
+
+import (
+	"regexp"
+)
+
+// Group prototype: name of host group to create for discovered host, containing LLD macros.
+type GroupPrototype struct {
+	Name string `json:"name"`
+}
+
+type GroupPrototypes []GroupPrototype
+
+// https://www.zabbix.com/documentation/3.0/manual/api/reference/hostprototype/object
+type HostPrototype struct {
+	HostId string `json:"hostid,omitempty"`
+	Host   string `json:"host"`
+	Name   string `json:"name,omitempty"`
+	Status Status `json:"status,string"`
+
+	// Id of discovery rule, used only when creating host prototypes.
+	RuleId string `json:"ruleid,omitempty"`
+
+	// Returned by selectDiscoveryRule query parameter.
+	DiscoveryRule *DiscoveryRule `json:"discoveryRule,omitempty"`
+
+	// Existing host groups and group prototypes for discovered hosts, templates to link them to.
+	// Returned by selectGroupLinks, selectGroupPrototypes and selectTemplates query parameters.
+	GroupLinks      HostGroupIds    `json:"groupLinks,omitempty"`
+	GroupPrototypes GroupPrototypes `json:"groupPrototypes,omitempty"`
+	Templates       TemplateIds     `json:"templates,omitempty"`
+}
+
+type HostPrototypes []HostPrototype
+
+var lldMacro = regexp.MustCompile(`\{#[A-Z0-9_.]+\}`)
+
+// Wrapper for hostprototype.get: https://www.zabbix.com/documentation/3.0/manual/api/reference/hostprototype/get
+func (api *API) HostPrototypesGet(params Params) (res HostPrototypes, err error) {
+	params = params.Clone()
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	err = api.callInto("hostprototype.get", params, &res)
+	return
+}
+
+// Wrapper for hostprototype.create: https://www.zabbix.com/documentation/3.0/manual/api/reference/hostprototype/create
+// Logs warning for host prototypes without LLD macro in Host: all discovered hosts would get the same name.
+func (api *API) HostPrototypesCreate(prototypes HostPrototypes) (err error) {
+	for _, prototype := range prototypes {
+		if !lldMacro.MatchString(prototype.Host) {
+			api.printf("Warning: host prototype %s has no LLD macro.", prototype.Host)
+		}
+	}
+
+	response, err := api.CallWithError("hostprototype.create", prototypes)
+	if err != nil {
+		return
+	}
+
+	hostids, err := response.ResultIDs("hostids")
+	if err != nil {
+		return
+	}
+	for i, id := range hostids {
+		prototypes[i].HostId = id
+	}
+	return
+}
+
+// Wrapper for hostprototype.delete: https://www.zabbix.com/documentation/3.0/manual/api/reference/hostprototype/delete
+// Cleans HostId in all prototypes elements if call succeed.
+func (api *API) HostPrototypesDelete(prototypes HostPrototypes) (err error) {
+	ids := make([]string, len(prototypes))
+	for i, prototype := range prototypes {
+		ids[i] = prototype.HostId
+	}
+
+	err = api.HostPrototypesDeleteByIds(ids)
+	if err == nil {
+		for i := range prototypes {
+			prototypes[i].HostId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for hostprototype.delete: https://www.zabbix.com/documentation/3.0/manual/api/reference/hostprototype/delete
+func (api *API) HostPrototypesDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("hostprototype.delete", ids)
+	if err != nil {
+		return
+	}
+
+	hostids, err := response.ResultIDs("hostids")
+	if err != nil {
+		return
+	}
+	if len(ids) != len(hostids) {
+		err = &ExpectedMore{len(ids), len(hostids)}
+	}
+	return
+}
@@ -0,0 +1,88 @@
+package zabbix_test
+
+import (
+	"bytes"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "."
+)
+
+func TestHostPrototypes(t *testing.T) {
+	var created []map[string]interface{}
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		switch call.Method {
+		case "discoveryrule.create":
+			return map[string]interface{}{"itemids": []string{"500"}}
+		case "hostprototype.create":
+			call.decodeParams(&created, t)
+			return map[string]interface{}{"hostids": []string{"600", "601"}}
+		case "hostprototype.get":
+			var params map[string]interface{}
+			call.decodeParams(&params, t)
+			if params["discoveryids"] != "500" || params["selectDiscoveryRule"] == nil {
+				t.Errorf("Unexpected params: %#v", params)
+			}
+			return []interface{}{map[string]interface{}{
+				"hostid": "600", "host": "{#VM.NAME}", "name": "VM {#VM.NAME}", "status": "0",
+				"discoveryRule":   map[string]string{"itemid": "500", "name": "VMs", "type": "2", "status": "0"},
+				"groupLinks":      []map[string]string{{"groupid": "2"}},
+				"groupPrototypes": []map[string]string{{"name": "VMs/{#VM.CLUSTER}"}},
+			}}
+		case "hostprototype.delete":
+			return map[string]interface{}{"hostids": []string{"600", "601"}}
+		}
+		t.Errorf("Unexpected method %s", call.Method)
+		return nil
+	})
+	defer server.Close()
+	var buf bytes.Buffer
+	api.Logger = log.New(&buf, "", 0)
+
+	rules := DiscoveryRules{{HostId: "10084", Key: "vm.discovery", Name: "VMs", Type: ZabbixTrapper}}
+	err := api.DiscoveryRulesCreate(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prototypes := HostPrototypes{
+		{
+			Host: "{#VM.NAME}", Name: "VM {#VM.NAME}", RuleId: rules[0].ItemId,
+			GroupLinks:      HostGroupIds{{"2"}},
+			GroupPrototypes: GroupPrototypes{{Name: "VMs/{#VM.CLUSTER}"}},
+			Templates:       TemplateIds{{TemplateId: "10001"}},
+		},
+		{Host: "static-vm", RuleId: rules[0].ItemId, GroupLinks: HostGroupIds{{"2"}}},
+	}
+	err = api.HostPrototypesCreate(prototypes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prototypes[0].HostId != "600" || prototypes[1].HostId != "601" {
+		t.Errorf("Ids are not set: %#v", prototypes)
+	}
+	if created[0]["ruleid"] != "500" || !reflect.DeepEqual(created[0]["templates"], []interface{}{map[string]interface{}{"templateid": "10001"}}) {
+		t.Errorf("Unexpected payload: %#v", created)
+	}
+	if strings.Contains(buf.String(), "{#VM.NAME} has no LLD macro") || !strings.Contains(buf.String(), "static-vm has no LLD macro") {
+		t.Errorf("Unexpected log:\n%s", buf.String())
+	}
+
+	prototypes2, err := api.HostPrototypesGet(Params{"discoveryids": "500", "selectDiscoveryRule": "extend", "selectGroupLinks": "extend", "selectGroupPrototypes": "extend"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prototypes2) != 1 || prototypes2[0].DiscoveryRule == nil || prototypes2[0].DiscoveryRule.ItemId != "500" {
+		t.Fatalf("Unexpected prototypes: %#v", prototypes2)
+	}
+	if prototypes2[0].GroupPrototypes[0].Name != "VMs/{#VM.CLUSTER}" || prototypes2[0].GroupLinks[0].GroupId != "2" {
+		t.Errorf("Unexpected groups: %#v", prototypes2[0])
+	}
+
+	err = api.HostPrototypesDelete(prototypes)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,9 +1,13 @@
 package zabbix_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"log"
 	"math/rand"
 	"reflect"
+	"strings"
 	"testing"
 
 	. "."
@@ -88,3 +92,307 @@ func TestHosts(t *testing.T) {
 		t.Errorf("Bad hosts: %#v", hosts)
 	}
 }
+
+func TestHostInventory(t *testing.T) {
+	api := getAPI(t)
+
+	group := CreateHostGroup(t)
+	defer DeleteHostGroup(group, t)
+
+	name := fmt.Sprintf("%s-%d", getHost(), rand.Int())
+	hosts := Hosts{{
+		Host:       name,
+		Name:       "Name for " + name,
+		GroupIds:   HostGroupIds{{group.GroupId}},
+		Interfaces: HostInterfaces{{DNS: name, Port: "42", Type: Agent, UseIP: 0, Main: 1}},
+		Inventory:  &HostInventory{Location: "Rack 42", Contact: "ops@example.com"},
+	}}
+	err := api.HostsCreate(hosts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer DeleteHost(&hosts[0], t)
+	if hosts[0].InventoryMode == nil || *hosts[0].InventoryMode != InventoryManual {
+		t.Errorf("Inventory mode is not set: %#v", hosts[0].InventoryMode)
+	}
+
+	hosts2, err := api.HostsGet(Params{"hostids": hosts[0].HostId, "selectInventory": []string{"location", "contact"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hosts2) != 1 || hosts2[0].Inventory == nil {
+		t.Fatalf("Bad hosts: %#v", hosts2)
+	}
+	if *hosts2[0].Inventory != *hosts[0].Inventory {
+		t.Errorf("Inventories are not equal:\n%#v\n%#v", hosts2[0].Inventory, hosts[0].Inventory)
+	}
+}
+
+func TestHostsGetInventory(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		return []interface{}{
+			map[string]interface{}{"hostid": "1", "host": "a", "inventory_mode": "1", "inventory": map[string]string{"location": "Rack 42", "contact": "ops"}},
+			map[string]interface{}{"hostid": "2", "host": "b", "inventory_mode": "-1", "inventory": []interface{}{}},
+		}
+	})
+	defer server.Close()
+
+	hosts, err := api.HostsGet(Params{"selectInventory": "extend"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hosts) != 2 {
+		t.Fatalf("Bad hosts: %#v", hosts)
+	}
+	if hosts[0].Inventory == nil || hosts[0].Inventory.Location != "Rack 42" || *hosts[0].InventoryMode != InventoryAutomatic {
+		t.Errorf("Bad host: %#v", hosts[0])
+	}
+	if hosts[1].Inventory != nil || *hosts[1].InventoryMode != InventoryDisabled {
+		t.Errorf("Bad host: %#v", hosts[1])
+	}
+}
+
+func TestHostCreateWithTemplates(t *testing.T) {
+	var methods []string
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		methods = append(methods, call.Method)
+		switch call.Method {
+		case "host.create":
+			var hosts []struct {
+				Templates []map[string]string `json:"templates"`
+			}
+			call.decodeParams(&hosts, t)
+			expected := []map[string]string{{"templateid": "1"}, {"templateid": "2"}, {"templateid": "3"}}
+			if len(hosts) != 1 || !reflect.DeepEqual(hosts[0].Templates, expected) {
+				t.Errorf("Unexpected hosts: %#v", hosts)
+			}
+			return map[string]interface{}{"hostids": []string{"10084"}}
+		case "host.get":
+			var params map[string]interface{}
+			call.decodeParams(&params, t)
+			if params["hostids"] != "10084" || params["selectParentTemplates"] == nil {
+				t.Errorf("Unexpected params: %#v", params)
+			}
+			return []interface{}{map[string]interface{}{
+				"hostid": "10084", "host": "web1", "name": "web1",
+				"parentTemplates": []map[string]string{
+					{"templateid": "1", "host": "Template OS Linux"},
+					{"templateid": "2", "host": "Template App Nginx"},
+					{"templateid": "3", "host": "Template App PHP-FPM"},
+				},
+			}}
+		}
+		t.Errorf("Unexpected method %s", call.Method)
+		return nil
+	})
+	defer server.Close()
+
+	host, err := api.HostCreateWithTemplates(Host{Host: "web1", GroupIds: HostGroupIds{{"2"}}}, []string{"1", "2", "3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if host.HostId != "10084" || len(host.ParentTemplates) != 3 || host.ParentTemplates[2].TemplateId != "3" {
+		t.Errorf("Unexpected host: %#v", host)
+	}
+	if !reflect.DeepEqual(methods, []string{"host.create", "host.get"}) {
+		t.Errorf("Unexpected methods: %v", methods)
+	}
+}
+
+func TestHostCreateWithConflictingTemplates(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		if call.Method != "host.create" {
+			t.Errorf("Unexpected method %s", call.Method)
+		}
+		return &Error{Code: -32602, Message: "Invalid params.", Data: `Item "agent.ping" already exists on "web1", inherited from another template.`}
+	})
+	defer server.Close()
+
+	_, err := api.HostCreateWithTemplates(Host{Host: "web1", GroupIds: HostGroupIds{{"2"}}}, []string{"1", "2"})
+	if e, ok := err.(*Error); !ok || e.Data == "" {
+		t.Errorf("Expected API error, got %#v", err)
+	}
+}
+
+func TestHostsGetWithMacros(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		var params map[string]interface{}
+		call.decodeParams(&params, t)
+		if params["selectMacros"] != "extend" || params["hostids"] != "10084" {
+			t.Errorf("Unexpected params: %#v", params)
+		}
+		return []interface{}{map[string]interface{}{
+			"hostid": "10084", "host": "db1",
+			"macros": []map[string]string{
+				{"hostmacroid": "1", "hostid": "10084", "macro": "{$SNMP_COMMUNITY}", "value": "public"},
+				{"hostmacroid": "2", "hostid": "10084", "macro": "{$DISK.PFREE.MIN}", "value": "15", "description": "Free space threshold"},
+			},
+		}}
+	})
+	defer server.Close()
+
+	hosts, err := api.HostsGetWithMacros(Params{"hostids": "10084"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hosts) != 1 {
+		t.Fatalf("Unexpected hosts: %#v", hosts)
+	}
+	expected := UserMacros{
+		{HostMacroId: "1", HostId: "10084", Macro: "{$SNMP_COMMUNITY}", Value: "public"},
+		{HostMacroId: "2", HostId: "10084", Macro: "{$DISK.PFREE.MIN}", Value: "15", Description: "Free space threshold"},
+	}
+	if !reflect.DeepEqual(hosts[0].Macros, expected) {
+		t.Errorf("Unexpected macros: %#v", hosts[0].Macros)
+	}
+}
+
+func TestHostsGetInMaintenance(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		var params map[string]interface{}
+		call.decodeParams(&params, t)
+		if call.Method != "host.get" || !reflect.DeepEqual(params["filter"], map[string]interface{}{"maintenance_status": float64(1)}) {
+			t.Errorf("Unexpected call %s: %#v", call.Method, params)
+		}
+		return []map[string]string{
+			{"hostid": "10084", "host": "db1", "maintenance_status": "1", "maintenanceid": "3"},
+		}
+	})
+	defer server.Close()
+
+	hosts, err := api.HostsGetInMaintenance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hosts) != 1 || hosts[0].MaintenanceStatus != InEffect || hosts[0].MaintenanceId != "3" {
+		t.Errorf("Unexpected hosts: %#v", hosts)
+	}
+}
+
+func TestHostsCreatePSK(t *testing.T) {
+	const psk = "1f87b595725ac58dd977beef14b97461a7c1045b9a1c963065002c5473194952"
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		switch call.Method {
+		case "host.update":
+			return map[string]interface{}{"hostids": []string{"10084"}}
+		case "host.get":
+			// Zabbix before 5.4 returns PSK
+			return []map[string]string{{"hostid": "10084", "host": "web1", "tls_connect": "2", "tls_psk_identity": "PSK web1", "tls_psk": psk}}
+		}
+		var hosts []map[string]interface{}
+		call.decodeParams(&hosts, t)
+		if call.Method != "host.create" || len(hosts) != 1 {
+			t.Fatalf("Unexpected call %s: %#v", call.Method, hosts)
+		}
+		h := hosts[0]
+		if h["tls_connect"] != float64(2) || h["tls_accept"] != float64(3) || h["tls_psk_identity"] != "PSK web1" || h["tls_psk"] != psk {
+			t.Errorf("Unexpected host: %#v", h)
+		}
+		return map[string]interface{}{"hostids": []string{"10084"}}
+	})
+	defer server.Close()
+	var buf bytes.Buffer
+	api.Logger = log.New(&buf, "", 0)
+
+	hosts := Hosts{{Host: "web1", GroupIds: HostGroupIds{{"2"}}, TLSConnect: PSK, TLSAccept: NoEncryption | PSK, TLSPSKIdentity: "PSK web1", TLSPSK: psk}}
+	err := api.HostsCreate(hosts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), psk) || !strings.Contains(buf.String(), "PSK web1") {
+		t.Errorf("Unexpected log:\n%s", buf.String())
+	}
+
+	for _, host := range []Host{
+		{Host: "web2", TLSConnect: PSK, TLSPSK: psk},
+		{Host: "web3", TLSAccept: PSK, TLSPSKIdentity: "PSK web3", TLSPSK: "secret"},
+		{Host: "web4", TLSConnect: PSK | Certificate, TLSPSKIdentity: "PSK web4", TLSPSK: psk},
+	} {
+		err = api.HostsCreate(Hosts{host})
+		if err == nil || strings.Contains(err.Error(), psk) {
+			t.Errorf("Unexpected error for %s: %v", host.Host, err)
+		}
+	}
+
+	buf.Reset()
+	got, err := api.HostGetById("10084")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.TLSPSK != psk {
+		t.Errorf("Unexpected host: %#v", got)
+	}
+	if strings.Contains(buf.String(), psk) || !strings.Contains(buf.String(), "PSK web1") {
+		t.Errorf("Unexpected log:\n%s", buf.String())
+	}
+
+	// PSK is not returned by host.get since Zabbix 5.4, so update of other fields passes
+	err = api.HostsUpdate(Hosts{{HostId: "10084", Host: "web1", TLSConnect: PSK}})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHostsDescription(t *testing.T) {
+	description := "Owner: DBA team <dba@example.com>\nRunbook: https://wiki.example.com/db?host=db1&view=full\n\tÜberwachung — 监控 🚀"
+	var stored interface{}
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		switch call.Method {
+		case "host.create", "host.update":
+			var hosts []map[string]interface{}
+			call.decodeParams(&hosts, t)
+			var present bool
+			stored, present = hosts[0]["description"]
+			if !present {
+				t.Errorf("Description is not sent: %#v", hosts[0])
+			}
+			return map[string]interface{}{"hostids": []string{"10084"}}
+		case "host.get":
+			return []interface{}{map[string]interface{}{"hostid": "10084", "host": "db1", "description": stored}}
+		}
+		t.Errorf("Unexpected method %s", call.Method)
+		return nil
+	})
+	defer server.Close()
+
+	err := api.HostsCreate(Hosts{{Host: "db1", GroupIds: HostGroupIds{{"2"}}, Description: &description}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored != description {
+		t.Errorf("Unexpected sent description: %q", stored)
+	}
+
+	host, err := api.HostGetById("10084")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if host.Description == nil || *host.Description != description {
+		t.Errorf("Unexpected description: %#v", host.Description)
+	}
+
+	empty := ""
+	host.Description = &empty
+	err = api.HostsUpdate(Hosts{*host})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored != "" {
+		t.Errorf("Unexpected sent description: %q", stored)
+	}
+	host, err = api.HostGetById("10084")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if host.Description != nil {
+		t.Errorf("Unexpected description: %q", *host.Description)
+	}
+
+	b, err := json.Marshal(Host{Host: "db1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "description") {
+		t.Errorf("Nil description is sent: %s", b)
+	}
+}
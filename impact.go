@@ -0,0 +1,67 @@
+package zabbix
+
+// Limits of objects fetched by ItemImpactLimited() on each level.
+type ImpactLimits struct {
+	Triggers int
+	Actions  int
+	Graphs   int
+}
+
+// Limits used by ItemImpact().
+var DefaultImpactLimits = ImpactLimits{Triggers: 100, Actions: 100, Graphs: 100}
+
+// Objects depending on item.
+type ImpactReport struct {
+	ItemId   string
+	Triggers Triggers // triggers referencing item in expressions
+	Actions  Actions  // actions with conditions on those triggers
+	Graphs   Graphs   // graphs plotting item
+
+	// Set if some level has more objects than limit; only first limit objects are reported then.
+	Truncated bool
+}
+
+// Gets triggers, actions and graphs depending on item with DefaultImpactLimits.
+func (api *API) ItemImpact(itemId string) (res ImpactReport, err error) {
+	return api.ItemImpactLimited(itemId, DefaultImpactLimits)
+}
+
+// Gets triggers, actions and graphs depending on item, fetching at most given number of objects of each kind.
+// Actions are fetched only for reported triggers.
+func (api *API) ItemImpactLimited(itemId string, limits ImpactLimits) (res ImpactReport, err error) {
+	res.ItemId = itemId
+
+	res.Triggers, err = api.TriggersGet(Params{"itemids": itemId, "limit": limits.Triggers + 1})
+	if err != nil {
+		return
+	}
+	if len(res.Triggers) > limits.Triggers {
+		res.Triggers = res.Triggers[:limits.Triggers]
+		res.Truncated = true
+	}
+
+	if len(res.Triggers) != 0 {
+		ids := make([]string, len(res.Triggers))
+		for i, trigger := range res.Triggers {
+			ids[i] = trigger.TriggerId
+		}
+		res.Actions, err = api.ActionsGet(Params{"triggerids": ids, "limit": limits.Actions + 1})
+		if err != nil {
+			return
+		}
+		if len(res.Actions) > limits.Actions {
+			res.Actions = res.Actions[:limits.Actions]
+			res.Truncated = true
+		}
+	}
+
+	res.Graphs, err = api.GraphsGet(Params{"itemids": itemId, "limit": limits.Graphs + 1})
+	if err != nil {
+		return
+	}
+	if len(res.Graphs) > limits.Graphs {
+		res.Graphs = res.Graphs[:limits.Graphs]
+		res.Truncated = true
+	}
+	return
+}
@@ -0,0 +1,57 @@
+package zabbix_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "."
+)
+
+func TestItemImpact(t *testing.T) {
+	var methods []string
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		methods = append(methods, call.Method)
+		var params map[string]interface{}
+		call.decodeParams(&params, t)
+		switch call.Method {
+		case "trigger.get":
+			if params["itemids"] != "23" || params["limit"] != float64(3) {
+				t.Errorf("Unexpected params: %#v", params)
+			}
+			return []map[string]string{{"triggerid": "13", "description": "Host is down", "priority": "5", "status": "0"}}
+		case "action.get":
+			if !reflect.DeepEqual(params["triggerids"], []interface{}{"13"}) || params["limit"] != float64(2) {
+				t.Errorf("Unexpected params: %#v", params)
+			}
+			return []map[string]string{{"actionid": "7", "name": "Notify admins", "eventsource": "0", "status": "0"}}
+		case "graph.get":
+			if params["itemids"] != "23" {
+				t.Errorf("Unexpected params: %#v", params)
+			}
+			return []map[string]string{
+				{"graphid": "1", "name": "Ping", "width": "900", "height": "200"},
+				{"graphid": "2", "name": "Availability", "width": "900", "height": "200"},
+			}
+		}
+		t.Errorf("Unexpected method %s", call.Method)
+		return nil
+	})
+	defer server.Close()
+
+	report, err := api.ItemImpactLimited("23", ImpactLimits{Triggers: 2, Actions: 1, Graphs: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Triggers) != 1 || report.Triggers[0].TriggerId != "13" {
+		t.Errorf("Unexpected triggers: %#v", report.Triggers)
+	}
+	if len(report.Actions) != 1 || report.Actions[0].Name != "Notify admins" {
+		t.Errorf("Unexpected actions: %#v", report.Actions)
+	}
+	if len(report.Graphs) != 1 || report.Graphs[0].GraphId != "1" || !report.Truncated {
+		t.Errorf("Unexpected graphs: %#v (truncated %v)", report.Graphs, report.Truncated)
+	}
+	if !reflect.DeepEqual(methods, []string{"trigger.get", "action.get", "graph.get"}) {
+		t.Errorf("Unexpected methods: %v", methods)
+	}
+}
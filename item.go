@@ -1,15 +1,33 @@
 package zabbix
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AlekSi/reflector"
 )
 
 type (
-	ItemType  int
-	ValueType int
-	DataType  int
-	DeltaType int
+	ItemType     int
+	ValueType    int
+	DataType     int
+	DeltaType    int
+	ItemFlag     int
+	AuthType     int
+	HTTPMethod   int
+	RetrieveMode int
+	ItemState    int
+
+	// How application-based helpers like ItemsGetByApplicationIds find items.
+	ItemGroupingMode int
 )
 
 const (
@@ -30,6 +48,11 @@ const (
 	TELNETAgent       ItemType = 14
 	Calculated        ItemType = 15
 	JMXAgent          ItemType = 16
+	SNMPTrap          ItemType = 17
+	DependentItem     ItemType = 18
+	HTTPAgent         ItemType = 19
+	SNMPAgent         ItemType = 20
+	Script            ItemType = 21
 
 	Float     ValueType = 0
 	Character ValueType = 1
@@ -45,28 +68,282 @@ const (
 	AsIs  DeltaType = 0
 	Speed DeltaType = 1
 	Delta DeltaType = 2
+
+	HTTPGet  HTTPMethod = 0
+	HTTPPost HTTPMethod = 1
+	HTTPPut  HTTPMethod = 2
+	HTTPHead HTTPMethod = 3
+
+	RetrieveBody    RetrieveMode = 0
+	RetrieveHeaders RetrieveMode = 1
+	RetrieveBoth    RetrieveMode = 2
+
+	PasswordAuth  AuthType = 0
+	PublicKeyAuth AuthType = 1
+
+	ApplicationGrouping    ItemGroupingMode = 0 // use applications, as before Zabbix 5.4
+	AutoGrouping           ItemGroupingMode = 1 // use applications before Zabbix 5.4 and Application tag since it
+	ApplicationTagGrouping ItemGroupingMode = 2 // always use Application tag
+
+	PlainItem      ItemFlag = 0
+	PrototypeItem  ItemFlag = 2
+	DiscoveredItem ItemFlag = 4
+
+	NormalState       ItemState = 0
+	NotSupportedState ItemState = 1
 )
 
 // https://www.zabbix.com/documentation/2.0/manual/appendix/api/item/definitions
 type Item struct {
-	ItemId      string    `json:"itemid,omitempty"`
-	Delay       int       `json:"delay"`
-	HostId      string    `json:"hostid"`
-	InterfaceId string    `json:"interfaceid,omitempty"`
-	Key         string    `json:"key_"`
-	Name        string    `json:"name"`
-	Type        ItemType  `json:"type"`
-	ValueType   string    `json:"value_type"`
-	LastValue   string    `json:"lastvalue"`
-	DataType    DataType  `json:"data_type"`
-	Delta       DeltaType `json:"delta"`
-	Description string    `json:"description"`
-	Error       string    `json:"error"`
-	History     int       `json:"history,omitempty"`
-	Trends      int       `json:"trends,omitempty"`
+	ItemId      string        `json:"itemid,omitempty"`
+	Delay       int           `json:"delay"`
+	HostId      string        `json:"hostid"`
+	InterfaceId string        `json:"interfaceid,omitempty"`
+	TemplateId  string        `json:"templateid,omitempty"`
+	Key         string        `json:"key_"`
+	Name        string        `json:"name"`
+	Type        ItemType      `json:"type"`
+	Status      Status        `json:"status"`
+	ValueType   string        `json:"value_type"`
+	LastValue   string        `json:"lastvalue"`
+	DataType    DataType      `json:"data_type"`
+	Delta       DeltaType     `json:"delta"`
+	Description string        `json:"description"`
+	Error       string        `json:"error"`
+	History     StoragePeriod `json:"history,omitempty"`
+	Trends      StoragePeriod `json:"trends,omitempty"`
+	Flags       ItemFlag      `json:"flags,omitempty,string"` // read-only
+
+	// Fields below used by SSH, TELNET, JMX and database monitor items. Params is executed script or SQL query.
+	// Password and PrivateKey are never logged.
+	AuthType   AuthType `json:"authtype,omitempty"`
+	Username   string   `json:"username,omitempty"`
+	Password   string   `json:"password,omitempty"`
+	PublicKey  string   `json:"publickey,omitempty"`
+	PrivateKey string   `json:"privatekey,omitempty"`
+	Params     string   `json:"params,omitempty"`
+
+	// Read-only time of last value from lastclock and lastns fields, zero if item never received value.
+	// Not sent on create or update.
+	LastClock time.Time `json:"-"`
+
+	// Read-only value received before LastValue. Empty if item received less than two values, or if lastvalue
+	// is not requested: prevvalue is meaningful only together with it. Not sent on create or update.
+	PrevValue string `json:"-"`
+
+	// Read-only time of next poll from nextcheck field returned by Zabbix before 2.2, zero if unknown.
+	// Not sent on create or update.
+	NextCheck time.Time `json:"-"`
+
+	// Interface polled by item, returned by selectInterfaces query parameter. Nil for items without interface,
+	// like active agent or trapper ones. Not sent on create or update, use InterfaceId.
+	Interface *HostInterface `json:"-"`
+
+	// Rule which created discovered item, returned by selectDiscoveryRule query parameter.
+	// Nil for other items. Not sent on create or update.
+	DiscoveryRule *DiscoveryRule `json:"-"`
+
+	// Custom intervals sent together with Delay in delay field (Zabbix 3.4+).
+	DelayIntervals []DelayInterval `json:"-"`
+
+	// Delay which can't be parsed into Delay and DelayIntervals, like user macro "{$DELAY}".
+	// Delay is 0 then. If not empty, it's sent as is instead of Delay, so clear it to change delay.
+	DelayRaw string `json:"-"`
+
+	// Format of time in log lines, like "yyyyMMdd:hhmmss". Used only by items with Log value type.
+	LogTimeFmt string `json:"logtimefmt,omitempty"`
+
+	// Time to wait for response, like "3s". Used only by HTTPAgent and Script items (Zabbix 6.0+).
+	Timeout string `json:"timeout,omitempty"`
+
+	// JMX connection string of JMXAgent items (Zabbix 3.4+).
+	JMXEndpoint string `json:"jmx_endpoint,omitempty"`
+
+	// Fields below used by HTTPAgent items (Zabbix 4.0+). StatusCodes is comma-separated list like "200,201-204".
+	// FollowRedirects is 0 or 1, nil keeps Zabbix default 1.
+	URL             string       `json:"url,omitempty"`
+	RequestMethod   HTTPMethod   `json:"request_method,omitempty"`
+	Posts           string       `json:"posts,omitempty"`
+	Headers         HTTPHeaders  `json:"headers,omitempty"`
+	StatusCodes     string       `json:"status_codes,omitempty"`
+	FollowRedirects *int         `json:"follow_redirects,omitempty,string"`
+	RetrieveMode    RetrieveMode `json:"retrieve_mode,omitempty"`
 
 	//returned from the slectApplications query parameter.
 	Applications Applications `json:"applications,omitempty"`
+
+	// Ids of applications to assign item to, sent as "applications" on create (Zabbix before 5.4 only).
+	ApplicationIds []string `json:"-"`
+
+	// Item tags (Zabbix 5.4+), returned by selectTags query parameter.
+	Tags Tags `json:"tags,omitempty"`
+}
+
+// Returns true if item received at least one value. LastValue of item without value is empty or "0",
+// so it is indistinguishable from real zero without this check.
+func (item Item) HasValue() bool {
+	return !item.LastClock.IsZero()
+}
+
+// Sends ApplicationIds instead of read-only Applications, and custom intervals or DelayRaw in delay.
+func (item Item) MarshalJSON() ([]byte, error) {
+	type plain Item
+	var delay interface{} = item.Delay
+	switch {
+	case item.DelayRaw != "":
+		delay = item.DelayRaw
+	case len(item.DelayIntervals) != 0:
+		delay = renderDelay(item.Delay, item.DelayIntervals)
+	}
+	return json.Marshal(struct {
+		plain
+		Delay        interface{} `json:"delay"`
+		Applications []string    `json:"applications,omitempty"`
+	}{plain(item), delay, item.ApplicationIds})
+}
+
+// Decodes delay string into Delay and DelayIntervals, lastclock and lastns into LastClock, prevvalue into PrevValue,
+// nextcheck into NextCheck, interfaces into Interface and discoveryRule into DiscoveryRule.
+func (item *Item) UnmarshalJSON(b []byte) (err error) {
+	type plain Item
+	aux := struct {
+		*plain
+		Delay     json.RawMessage `json:"delay"`
+		LastClock json.RawMessage `json:"lastclock"`
+		LastNs    json.RawMessage `json:"lastns"`
+		LastValue *string         `json:"lastvalue"`
+		PrevValue string          `json:"prevvalue"`
+		NextCheck json.RawMessage `json:"nextcheck"`
+
+		Interfaces    []interface{}   `json:"interfaces"`
+		DiscoveryRule json.RawMessage `json:"discoveryRule"`
+	}{plain: (*plain)(item)}
+	err = json.Unmarshal(b, &aux)
+	if err != nil {
+		return
+	}
+
+	// not discovered items have empty array
+	if len(aux.DiscoveryRule) != 0 && !isEmptyArray(aux.DiscoveryRule) && string(aux.DiscoveryRule) != "null" {
+		item.DiscoveryRule = new(DiscoveryRule)
+		if err = json.Unmarshal(aux.DiscoveryRule, item.DiscoveryRule); err != nil {
+			return
+		}
+	}
+
+	if len(aux.Interfaces) != 0 {
+		// reflector expects objects only
+		for _, iface := range aux.Interfaces {
+			if _, ok := iface.(map[string]interface{}); !ok {
+				return fmt.Errorf("Failed to decode item interface %v.", iface)
+			}
+		}
+		var ifaces HostInterfaces
+		if err = reflector.MapsToStructs2(aux.Interfaces, &ifaces, reflector.Strconv, "json"); err != nil {
+			return
+		}
+		if len(ifaces) != 0 {
+			item.Interface = &ifaces[0]
+		}
+	}
+	err = item.unmarshalLastClock(aux.LastClock, aux.LastNs)
+	if err != nil {
+		return
+	}
+	// lastvalue is decoded here to see if it's requested
+	if aux.LastValue != nil {
+		item.LastValue = *aux.LastValue
+	}
+	// items without values have "0" as prevvalue
+	if aux.LastValue != nil && (len(aux.LastClock) == 0 || item.HasValue()) {
+		item.PrevValue = aux.PrevValue
+	}
+
+	var nextCheck string
+	if nextCheck, err = rawString(aux.NextCheck); err != nil {
+		return
+	}
+	if item.NextCheck, err = parseUnixTime(nextCheck); err != nil {
+		return
+	}
+
+	if len(aux.Delay) == 0 || string(aux.Delay) == "null" {
+		return
+	}
+	var s string
+	if aux.Delay[0] != '"' {
+		s = string(aux.Delay)
+	} else if err = json.Unmarshal(aux.Delay, &s); err != nil {
+		return
+	}
+	// keep delay which can't be parsed, like user macro, instead of failing decoding of all items
+	if item.Delay, item.DelayIntervals, err = parseDelay(s); err != nil {
+		item.Delay, item.DelayIntervals, item.DelayRaw, err = 0, nil, s, nil
+	}
+	return
+}
+
+func (item *Item) unmarshalLastClock(clock, ns json.RawMessage) (err error) {
+	var c, n string
+	if c, err = rawString(clock); err != nil {
+		return
+	}
+	if n, err = rawString(ns); err != nil {
+		return
+	}
+	item.LastClock, err = parseUnixTime(c)
+	if err != nil || item.LastClock.IsZero() || n == "" {
+		return
+	}
+	nsec, err := strconv.ParseInt(n, 10, 64)
+	if err != nil {
+		return fmt.Errorf("Failed to parse lastns %q.", n)
+	}
+	item.LastClock = item.LastClock.Add(time.Duration(nsec))
+	return
+}
+
+// History or trends storage period: time unit string like "90d" (Zabbix 3.4+)
+// or number of days (older versions). "0" means do not keep.
+type StoragePeriod string
+
+// Accepts both string and legacy integer forms.
+func (p *StoragePeriod) UnmarshalJSON(b []byte) (err error) {
+	var s string
+	if err = json.Unmarshal(b, &s); err == nil {
+		*p = StoragePeriod(s)
+		return
+	}
+
+	var n json.Number
+	if err = json.Unmarshal(b, &n); err == nil {
+		*p = StoragePeriod(n.String())
+	}
+	return
+}
+
+// Returns storage period in whole days. Plain number is number of days.
+func (p StoragePeriod) Days() (days int, err error) {
+	s := strings.TrimSpace(string(p))
+	if s == "" {
+		err = fmt.Errorf("Empty storage period.")
+		return
+	}
+
+	unit := 86400 // plain number is days for compatibility
+	suffixes := map[byte]int{'s': 1, 'm': 60, 'h': 3600, 'd': 86400, 'w': 7 * 86400}
+	if u, ok := suffixes[s[len(s)-1]]; ok {
+		unit = u
+		s = s[:len(s)-1]
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		err = fmt.Errorf("Failed to parse storage period %q.", string(p))
+		return
+	}
+	days = n * unit / 86400
+	return
 }
 
 type ItemResponse struct {
@@ -78,6 +355,33 @@ type ItemResponse struct {
 
 type Items []Item
 
+// JMX endpoint used by Zabbix frontend by default.
+const DefaultJMXEndpoint = "service:jmx:rmi:///jndi/rmi://{HOST.CONN}:{HOST.PORT}/jmxrmi"
+
+// HTTP headers of HTTPAgent item, name to value.
+type HTTPHeaders map[string]string
+
+// Accepts empty array returned by Zabbix for item without headers.
+func (h *HTTPHeaders) UnmarshalJSON(b []byte) error {
+	if isEmptyArray(b) {
+		*h = nil
+		return nil
+	}
+	return json.Unmarshal(b, (*map[string]string)(h))
+}
+
+// Returned by ItemsUpdate for items inherited from templates.
+var ErrTemplatedItem = errors.New("Templated item should be updated on template.")
+
+// Returned when item being created already exists, for example, created by concurrent caller.
+type ItemAlreadyExists struct {
+	Err *Error
+}
+
+func (e *ItemAlreadyExists) Error() string {
+	return fmt.Sprintf("Item already exists: %s", e.Err)
+}
+
 // Converts slice to map by key. Panics if there are duplicate keys.
 func (items Items) ByKey() (res map[string]Item) {
 	res = make(map[string]Item, len(items))
@@ -93,6 +397,7 @@ func (items Items) ByKey() (res map[string]Item) {
 
 // Wrapper for item.get https://www.zabbix.com/documentation/2.0/manual/appendix/api/item/get
 func (api *API) ItemsGet(params Params) (res Items, err error) {
+	params = params.Clone()
 	if _, present := params["output"]; !present {
 		params["output"] = "extend"
 	}
@@ -107,22 +412,619 @@ func (api *API) ItemsGet(params Params) (res Items, err error) {
 	return
 }
 
+// Gets items with preservekeys option, mapped by item Id.
+func (api *API) ItemsGetMap(params Params) (res map[string]Item, err error) {
+	params = params.Clone()
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	params["preservekeys"] = true
+
+	// result is object keyed by Ids, or empty array if nothing is found
+	var result json.RawMessage
+	err = api.callInto("item.get", params, &result)
+	if err != nil {
+		return
+	}
+	res = make(map[string]Item)
+	if !isEmptyArray(result) {
+		err = json.Unmarshal(result, &res)
+	}
+	return
+}
+
 // Gets items by application Id.
 func (api *API) ItemsGetByApplicationId(id string) (res Items, err error) {
-	return api.ItemsGet(Params{"applicationids": id})
+	return api.ItemsGetByApplicationIds([]string{id})
+}
+
+// Gets items by application Ids. Returns ErrEmptyIds if ids is empty.
+// If api.ItemGroupingMode selects Application tag, items having that tag with any of ids as value are returned
+// instead. This mapping is imperfect: Zabbix 5.4 upgrade converts applications to tags with application names,
+// not Ids, so callers should pass application names in that mode.
+func (api *API) ItemsGetByApplicationIds(ids []string) (res Items, err error) {
+	if len(ids) == 0 {
+		err = ErrEmptyIds
+		return
+	}
+
+	tags := api.ItemGroupingMode == ApplicationTagGrouping
+	if api.ItemGroupingMode == AutoGrouping {
+		var v version
+		v, err = api.serverVersion()
+		if err != nil {
+			return
+		}
+		tags = v.atLeast(5, 4)
+	}
+	if !tags {
+		return api.ItemsGet(Params{"applicationids": ids})
+	}
+
+	filters := make(TagFilters, len(ids))
+	for i, id := range ids {
+		filters[i] = TagFilter{Tag: "Application", Value: id, Operator: TagEquals}
+	}
+	return api.ItemsGet(filters.params(Params{}, EvalAndOr))
+}
+
+// Gets items of host with tag equal to value, with their tags selected. Empty hostId means all hosts.
+// Item tags were added in Zabbix 5.4 together with removal of applications, so error is returned
+// for earlier versions: they ignore unknown tags parameter and would return all items of the host.
+func (api *API) ItemsGetByTag(hostId, tag, value string) (res Items, err error) {
+	v, err := api.serverVersion()
+	if err != nil {
+		return
+	}
+	if !v.atLeast(5, 4) {
+		err = fmt.Errorf("Item tags are not supported by Zabbix %s, 5.4 or later is required.", v)
+		return
+	}
+
+	params := Params{"selectTags": "extend"}
+	if hostId != "" {
+		params["hostids"] = hostId
+	}
+	filters := TagFilters{{Tag: tag, Value: value, Operator: TagEquals}}
+	return api.ItemsGet(filters.params(params, EvalAndOr))
+}
+
+// Counts items matching params by their type. Only item type is requested, and buffered raw response is decoded
+// item by item, so Items are not built. Still, all matching items are transferred and read into memory: to count
+// items of a few known types it's cheaper to call item.get with countOutput and type filter once per type.
+func (api *API) ItemsCountByType(params Params) (res map[ItemType]int, err error) {
+	params = params.Clone()
+	params["output"] = []string{"type"}
+	b, err := api.callBytes("item.get", params)
+	if err != nil {
+		return
+	}
+
+	res = make(map[ItemType]int)
+	d := json.NewDecoder(bytes.NewReader(b))
+	if _, err = d.Token(); err != nil { // {
+		return
+	}
+	for d.More() {
+		var key json.Token
+		key, err = d.Token()
+		if err != nil {
+			return
+		}
+		switch key {
+		case "error":
+			var e *Error
+			if err = d.Decode(&e); err != nil {
+				return
+			}
+			if e != nil {
+				return nil, e
+			}
+		case "result":
+			if _, err = d.Token(); err != nil { // [
+				return
+			}
+			for d.More() {
+				var item struct {
+					Type json.Number `json:"type"`
+				}
+				if err = d.Decode(&item); err != nil {
+					return
+				}
+				var t int64
+				if t, err = item.Type.Int64(); err != nil {
+					return
+				}
+				res[ItemType(t)]++
+			}
+			if _, err = d.Token(); err != nil { // ]
+				return
+			}
+		default:
+			var skip json.RawMessage
+			if err = d.Decode(&skip); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// Gets items including web scenario items of WebItem type, which item.get excludes by default.
+func (api *API) ItemsGetIncludingWeb(params Params) (res Items, err error) {
+	params = params.Clone()
+	params["webitems"] = true
+	return api.ItemsGet(params)
+}
+
+// Gets items created by low-level discovery on given host.
+func (api *API) ItemsGetDiscovered(hostId string) (res Items, err error) {
+	return api.ItemsGet(Params{"hostids": hostId, "filter": map[string]interface{}{"flags": DiscoveredItem}})
+}
+
+// Gets items by host group Id.
+func (api *API) ItemsGetByGroupId(id string) (res Items, err error) {
+	return api.ItemsGetByGroupIds([]string{id})
+}
+
+// Gets items defined on template with given Id. Their copies on linked hosts are not returned,
+// use ItemsGetByGroupId or filter by TemplateId for them.
+func (api *API) ItemsGetByTemplateId(id string) (res Items, err error) {
+	return api.ItemsGet(Params{"templateids": id})
+}
+
+// Gets items by host group Ids. Returns ErrEmptyIds if ids is empty.
+func (api *API) ItemsGetByGroupIds(ids []string) (res Items, err error) {
+	if len(ids) == 0 {
+		err = ErrEmptyIds
+		return
+	}
+	return api.ItemsGet(Params{"groupids": ids})
+}
+
+// Gets items changed since given time, intended for incremental sync of cached items.
+// Zabbix doesn't track modification time of item configuration: item.get has no mtime field to filter on,
+// and lastclock is the time of last received value, not of last change. So for now this method
+// returns all items and callers have to diff them against their cache. Time argument is unused:
+// it is reserved for the time Zabbix will provide such a filter.
+func (api *API) ItemsGetChangedSince(_ time.Time) (res Items, err error) {
+	return api.ItemsGet(Params{})
+}
+
+// Gets last values of items, mapped by item Id. Items without values are absent.
+func (api *API) ItemsGetLastValues(ids []string) (res map[string]string, err error) {
+	var items []struct {
+		ItemId    string `json:"itemid"`
+		LastClock string `json:"lastclock"`
+		LastValue string `json:"lastvalue"`
+	}
+	err = api.callInto("item.get", Params{"itemids": ids, "output": []string{"itemid", "lastclock", "lastvalue"}}, &items)
+	if err != nil {
+		return
+	}
+
+	res = make(map[string]string, len(items))
+	for _, item := range items {
+		if item.LastClock != "" && item.LastClock != "0" {
+			res[item.ItemId] = item.LastValue
+		}
+	}
+	return
+}
+
+// Polls item every poll interval until it gets a value and returns it.
+// Item gets a value when its lastclock is set, so empty string received by text item is a value too.
+// If item doesn't exist, *ExpectedOneResult is returned immediately. If ctx expires first, ctx.Err() is returned.
+func (api *API) WaitForItemValue(ctx context.Context, itemId string, poll time.Duration) (value string, err error) {
+	params := Params{"itemids": itemId, "output": []string{"itemid", "lastclock", "lastvalue"}}
+	for {
+		var items []struct {
+			LastClock string `json:"lastclock"`
+			LastValue string `json:"lastvalue"`
+		}
+		err = api.callInto("item.get", params, &items)
+		if err != nil {
+			return
+		}
+		if len(items) != 1 {
+			e := ExpectedOneResult(len(items))
+			err = &e
+			return
+		}
+		if items[0].LastClock != "" && items[0].LastClock != "0" {
+			value = items[0].LastValue
+			return
+		}
+
+		timer := time.NewTimer(poll)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			err = ctx.Err()
+			return
+		case <-timer.C:
+		}
+	}
 }
 
 // Wrapper for item.create: https://www.zabbix.com/documentation/2.0/manual/appendix/api/item/create
+// Applications were removed in Zabbix 5.4, so ApplicationIds are rejected for that and later versions.
+// If api.SkipExistingItems is set, items already existing on their hosts are not created,
+// but their ItemId is filled, so call is safe to retry.
+// JMXAgent items without JMXEndpoint get DefaultJMXEndpoint.
+// Zero Delay is rejected with ValidationErrors for polled item types, see Item.Validate().
 func (api *API) ItemsCreate(items Items) (err error) {
+	err = api.checkItemApplications(items)
+	if err != nil {
+		return
+	}
+	err = items.validateDelays()
+	if err != nil {
+		return
+	}
+	for i := range items {
+		if items[i].Type == JMXAgent && items[i].JMXEndpoint == "" {
+			items[i].JMXEndpoint = DefaultJMXEndpoint
+		}
+	}
+
+	if api.SkipExistingItems {
+		return api.itemsCreateSkipExisting(items)
+	}
+	return api.itemsCreate(items)
+}
+
+// Creates items which don't exist yet, fills ItemId of existing ones.
+// "Already exists" error caused by concurrent creator is treated as success if all items are found after it.
+func (api *API) itemsCreateSkipExisting(items Items) (err error) {
+	if len(items) == 0 {
+		return
+	}
+
+	existing, err := api.existingItemIds(items)
+	if err != nil {
+		return
+	}
+
+	var toCreate Items
+	var createIndexes []int
+	for i := range items {
+		if id, present := existing[[2]string{items[i].HostId, items[i].Key}]; present {
+			items[i].ItemId = id
+		} else {
+			toCreate = append(toCreate, items[i])
+			createIndexes = append(createIndexes, i)
+		}
+	}
+	if len(toCreate) == 0 {
+		return
+	}
+
+	err = api.itemsCreate(toCreate)
+	if err == nil {
+		for i, item := range toCreate {
+			items[createIndexes[i]].ItemId = item.ItemId
+		}
+		return
+	}
+	if !isAlreadyExists(err) {
+		return
+	}
+
+	// some items were created concurrently, so re-fetch them
+	createErr := err
+	existing, err = api.existingItemIds(toCreate)
+	if err != nil {
+		return
+	}
+	for _, i := range createIndexes {
+		id, present := existing[[2]string{items[i].HostId, items[i].Key}]
+		if !present {
+			err = createErr
+			return
+		}
+		items[i].ItemId = id
+	}
+	return
+}
+
+func (api *API) itemsCreate(items Items) (err error) {
 	response, err := api.CallWithError("item.create", items)
 	if err != nil {
 		return
 	}
 
-	result := response.Result.(map[string]interface{})
-	itemids := result["itemids"].([]interface{})
+	itemids, err := response.ResultIDs("itemids")
+	if err != nil {
+		return
+	}
 	for i, id := range itemids {
-		items[i].ItemId = id.(string)
+		items[i].ItemId = id
+	}
+	return
+}
+
+// Gets items with their interfaces.
+func (api *API) ItemsGetWithInterface(params Params) (res Items, err error) {
+	params = params.Clone()
+	params["selectInterfaces"] = "extend"
+	return api.ItemsGet(params)
+}
+
+// Gets items discovered by low-level discovery rule, with DiscoveryRule filled.
+func (api *API) ItemsGetByDiscoveryRule(ruleId string) (res Items, err error) {
+	rules, err := api.DiscoveryRulesGet(Params{"itemids": ruleId, "output": []string{"itemid", "hostid"}})
+	if err != nil {
+		return
+	}
+	if len(rules) != 1 {
+		e := ExpectedOneResult(len(rules))
+		err = &e
+		return
+	}
+
+	items, err := api.ItemsGet(Params{
+		"hostids":             rules[0].HostId,
+		"filter":              Params{"flags": DiscoveredItem},
+		"selectDiscoveryRule": []string{"itemid", "name", "key_"},
+	})
+	if err != nil {
+		return
+	}
+	for _, item := range items {
+		if item.DiscoveryRule != nil && item.DiscoveryRule.ItemId == ruleId {
+			res = append(res, item)
+		}
+	}
+	return
+}
+
+// Creates items on given host polled via its interface of given type: fills HostId and InterfaceId
+// of all items. If host has several interfaces of that type, main one is used.
+func (api *API) ItemsCreateOnInterface(items Items, hostId string, ifaceType InterfaceType) (err error) {
+	ifaces, err := api.HostInterfacesGet(Params{"hostids": hostId, "filter": map[string]interface{}{"type": ifaceType}})
+	if err != nil {
+		return
+	}
+
+	var iface *HostInterface
+	for i := range ifaces {
+		if len(ifaces) == 1 || ifaces[i].Main == 1 {
+			iface = &ifaces[i]
+			break
+		}
+	}
+	if iface == nil {
+		err = fmt.Errorf("Host %s has no main interface of type %d (found %d interfaces).", hostId, ifaceType, len(ifaces))
+		return
+	}
+
+	for i := range items {
+		items[i].HostId = hostId
+		items[i].InterfaceId = iface.InterfaceId
+	}
+	return api.ItemsCreate(items)
+}
+
+// Creates items on given template: fills HostId of all items with template Id and clears InterfaceId,
+// as templates have no interfaces. Error is returned if there is no template with that Id,
+// for example, if it's Id of host.
+func (api *API) ItemsCreateOnTemplate(templateId string, items Items) (err error) {
+	templates, err := api.TemplatesGet(Params{"templateids": templateId, "output": []string{"templateid"}})
+	if err != nil {
+		return
+	}
+	if len(templates) != 1 {
+		err = fmt.Errorf("%s is not Id of template.", templateId)
+		return
+	}
+
+	for i := range items {
+		items[i].HostId = templateId
+		items[i].InterfaceId = ""
+	}
+	return api.ItemsCreate(items)
+}
+
+// Returns error if some items have ApplicationIds, but Zabbix doesn't support applications.
+func (api *API) checkItemApplications(items Items) error {
+	for _, item := range items {
+		if len(item.ApplicationIds) == 0 {
+			continue
+		}
+
+		v, err := api.serverVersion()
+		if err != nil {
+			return err
+		}
+		if v.atLeast(5, 4) {
+			return fmt.Errorf("Item %s has applications, but they are not supported by Zabbix %s.", item.Key, v)
+		}
+		return nil
+	}
+	return nil
+}
+
+// Wrapper for item.update: https://www.zabbix.com/documentation/2.0/manual/appendix/api/item/update
+// Discovered items can't be updated directly, their prototypes should be updated instead.
+// Items inherited from templates are rejected with ErrTemplatedItem unless api.UpdateTemplatedItems is set:
+// Zabbix allows to change only some of their fields.
+func (api *API) ItemsUpdate(items Items) (err error) {
+	for _, item := range items {
+		if item.Flags == DiscoveredItem {
+			err = fmt.Errorf("Item %s is discovered, update its prototype instead.", item.ItemId)
+			return
+		}
+		if !api.UpdateTemplatedItems && item.TemplateId != "" && item.TemplateId != "0" {
+			err = fmt.Errorf("Item %s is inherited from template item %s: %w", item.ItemId, item.TemplateId, ErrTemplatedItem)
+			return
+		}
+	}
+
+	response, err := api.CallWithError("item.update", items)
+	if err != nil {
+		return
+	}
+
+	itemids, err := response.ResultIDs("itemids")
+	if err != nil {
+		return
+	}
+	if len(items) != len(itemids) {
+		err = &ExpectedMore{len(items), len(itemids)}
+	}
+	return
+}
+
+// Changes keys of host items in place with item.update, so their history is kept.
+// renames maps old key to new one. Error is returned before any update if several keys are renamed to the same one,
+// host has no item with old key, already has item with new key which is not renamed too,
+// or item is inherited from template or discovered: their keys can't be changed.
+// Keys mapped to themselves are left as is, keys may be swapped.
+func (api *API) ItemsRenameKeys(hostId string, renames map[string]string) (err error) {
+	oldKeys := make([]string, 0, len(renames))
+	keys := make([]string, 0, len(renames)*2)
+	for oldKey, newKey := range renames {
+		oldKeys = append(oldKeys, oldKey)
+		keys = append(keys, oldKey, newKey)
+	}
+	sort.Strings(oldKeys)
+
+	renamedTo := make(map[string]string, len(renames))
+	for _, oldKey := range oldKeys {
+		newKey := renames[oldKey]
+		if other, ok := renamedTo[newKey]; ok {
+			err = fmt.Errorf("Can't rename item keys %s and %s to the same key %s.", other, oldKey, newKey)
+			return
+		}
+		renamedTo[newKey] = oldKey
+	}
+
+	items, err := api.ItemsGet(Params{
+		"hostids": hostId,
+		"filter":  Params{"key_": keys},
+		"output":  []string{"itemid", "key_", "templateid", "flags"},
+	})
+	if err != nil {
+		return
+	}
+	byKey := make(map[string]Item, len(items))
+	for _, item := range items {
+		byKey[item.Key] = item
+	}
+
+	updates := make([]Params, 0, len(oldKeys))
+	for _, oldKey := range oldKeys {
+		newKey := renames[oldKey]
+		item, ok := byKey[oldKey]
+		// item with new key is not a collision if its key is changed too
+		next, renamedAway := renames[newKey]
+		switch {
+		case !ok:
+			err = fmt.Errorf("Host %s has no item with key %s.", hostId, oldKey)
+		case newKey == oldKey:
+			continue
+		case byKey[newKey].ItemId != "" && (!renamedAway || next == newKey):
+			err = fmt.Errorf("Can't rename item key %s: host %s already has item %s with key %s.", oldKey, hostId, byKey[newKey].ItemId, newKey)
+		case item.Flags == DiscoveredItem:
+			err = fmt.Errorf("Item %s is discovered, rename its prototype instead.", item.ItemId)
+		case item.TemplateId != "" && item.TemplateId != "0":
+			err = fmt.Errorf("Item %s is inherited from template item %s: %w", item.ItemId, item.TemplateId, ErrTemplatedItem)
+		}
+		if err != nil {
+			return
+		}
+		updates = append(updates, Params{"itemid": item.ItemId, "key_": newKey})
+	}
+	if len(updates) == 0 {
+		return
+	}
+
+	response, err := api.CallWithError("item.update", updates)
+	if err != nil {
+		return
+	}
+
+	itemids, err := response.ResultIDs("itemids")
+	if err == nil && len(updates) != len(itemids) {
+		err = &ExpectedMore{len(updates), len(itemids)}
+	}
+	return
+}
+
+// Returns Ids of existing items with the same HostId and Key as given items, fetched with a single item.get call.
+func (api *API) existingItemIds(items Items) (res map[[2]string]string, err error) {
+	hostIds := make([]string, 0, len(items))
+	keys := make([]string, 0, len(items))
+	seenHosts := make(map[string]bool)
+	for _, item := range items {
+		if !seenHosts[item.HostId] {
+			seenHosts[item.HostId] = true
+			hostIds = append(hostIds, item.HostId)
+		}
+		keys = append(keys, item.Key)
+	}
+
+	found, err := api.ItemsGet(Params{
+		"output":  []string{"itemid", "hostid", "key_"},
+		"hostids": hostIds,
+		"filter":  map[string]interface{}{"key_": keys},
+	})
+	if err != nil {
+		return
+	}
+	res = make(map[[2]string]string, len(found))
+	for _, item := range found {
+		res[[2]string{item.HostId, item.Key}] = item.ItemId
+	}
+	return
+}
+
+// Creates or updates items: items are looked up by HostId and Key with a single item.get call,
+// found ones are updated (ItemId is copied from existing item), others are created.
+// Fills ItemId in all items elements if call succeed.
+// This is best-effort: if concurrent caller creates the same item between lookup and create,
+// Zabbix error is returned as *ItemAlreadyExists.
+func (api *API) ItemsUpsert(items Items) (err error) {
+	if len(items) == 0 {
+		return
+	}
+
+	existing, err := api.existingItemIds(items)
+	if err != nil {
+		return
+	}
+
+	var toUpdate, toCreate Items
+	var createIndexes []int
+	for i := range items {
+		id, present := existing[[2]string{items[i].HostId, items[i].Key}]
+		if present {
+			items[i].ItemId = id
+			toUpdate = append(toUpdate, items[i])
+		} else {
+			toCreate = append(toCreate, items[i])
+			createIndexes = append(createIndexes, i)
+		}
+	}
+
+	if len(toUpdate) != 0 {
+		err = api.ItemsUpdate(toUpdate)
+		if err != nil {
+			return
+		}
+	}
+	if len(toCreate) != 0 {
+		err = api.itemsCreate(toCreate)
+		if isAlreadyExists(err) {
+			err = &ItemAlreadyExists{err.(*Error)}
+		}
+		if err != nil {
+			return
+		}
+		for i, item := range toCreate {
+			items[createIndexes[i]].ItemId = item.ItemId
+		}
 	}
 	return
 }
@@ -151,16 +1053,121 @@ func (api *API) ItemsDeleteByIds(ids []string) (err error) {
 		return
 	}
 
-	result := response.Result.(map[string]interface{})
-	itemids1, ok := result["itemids"].([]interface{})
-	l := len(itemids1)
-	if !ok {
-		// some versions actually return map there
-		itemids2 := result["itemids"].(map[string]interface{})
-		l = len(itemids2)
+	itemids, err := response.ResultIDs("itemids")
+	if err != nil {
+		return
+	}
+	if len(ids) != len(itemids) {
+		err = &ExpectedMore{len(ids), len(itemids)}
+	}
+	return
+}
+
+// Get parameters which limit set of returned items. Other ones, like output, webitems or inherited flags,
+// don't narrow it or even widen it.
+var filteringParams = map[string]bool{
+	"itemids": true, "hostids": true, "groupids": true, "templateids": true, "filter": true, "search": true,
+}
+
+// Returns true if get params contain non-empty filtering ones.
+func hasFilter(params Params) bool {
+	for key, value := range params {
+		if filteringParams[key] && !isEmptyParam(reflect.ValueOf(value)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns true for nil and empty maps, slices and strings of any type, which Zabbix ignores.
+// Maps with only such values, like filter with empty Ids, are empty too.
+func isEmptyParam(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil() || isEmptyParam(v.Elem())
+	case reflect.Slice, reflect.Array, reflect.String:
+		return v.Len() == 0
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			if !isEmptyParam(v.MapIndex(key)) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// Deletes items matching item.get params, fetching only their Ids first, and returns number of deleted items.
+// Params without itemids, hostids, groupids, templateids, filter or search, or with only empty ones, would match
+// all items, so ErrEmptyFilter is returned for them unless "all": true is given explicitly.
+func (api *API) ItemsDeleteByFilter(params Params) (count int, err error) {
+	params = params.Clone()
+	all, _ := params["all"].(bool)
+	delete(params, "all")
+	if !all && !hasFilter(params) {
+		err = ErrEmptyFilter
+		return
+	}
+
+	params["output"] = []string{"itemid"}
+	var items []struct {
+		ItemId string `json:"itemid"`
 	}
-	if len(ids) != l {
-		err = &ExpectedMore{len(ids), l}
+	err = api.callInto("item.get", params, &items)
+	if err != nil || len(items) == 0 {
+		return
+	}
+
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ItemId
+	}
+	err = api.ItemsDeleteByIds(ids)
+	if err == nil {
+		count = len(ids)
+	}
+	return
+}
+
+// Follows templateid references of item through all levels of template nesting and returns
+// the originating item on top-level template together with that template.
+// Error is returned if item is not inherited and is not on template itself, or if references form a cycle.
+func (api *API) ResolveItemOrigin(itemId string) (item Item, template Template, err error) {
+	seen := make(map[string]bool)
+	for id := itemId; ; {
+		if seen[id] {
+			err = fmt.Errorf("Item %s has cyclic templateid references at item %s.", itemId, id)
+			return
+		}
+		seen[id] = true
+
+		var items Items
+		items, err = api.ItemsGet(Params{"itemids": id})
+		if err != nil {
+			return
+		}
+		if len(items) != 1 {
+			e := ExpectedOneResult(len(items))
+			err = &e
+			return
+		}
+		item = items[0]
+		if item.TemplateId == "" || item.TemplateId == "0" {
+			break
+		}
+		id = item.TemplateId
+	}
+
+	t, err := api.TemplateGetById(item.HostId)
+	if _, ok := err.(*ExpectedOneResult); ok {
+		err = fmt.Errorf("Item %s is not inherited from template and is not on template.", itemId)
+	}
+	if err != nil {
+		return
 	}
+	template = *t
 	return
 }
@@ -0,0 +1,174 @@
+package zabbix
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var itemTypeNames = map[ItemType]string{
+	ZabbixAgent:       "ZabbixAgent",
+	SNMPv1Agent:       "SNMPv1Agent",
+	ZabbixTrapper:     "ZabbixTrapper",
+	SimpleCheck:       "SimpleCheck",
+	SNMPv2Agent:       "SNMPv2Agent",
+	ZabbixInternal:    "ZabbixInternal",
+	SNMPv3Agent:       "SNMPv3Agent",
+	ZabbixAgentActive: "ZabbixAgentActive",
+	ZabbixAggregate:   "ZabbixAggregate",
+	WebItem:           "WebItem",
+	ExternalCheck:     "ExternalCheck",
+	DatabaseMonitor:   "DatabaseMonitor",
+	IPMIAgent:         "IPMIAgent",
+	SSHAgent:          "SSHAgent",
+	TELNETAgent:       "TELNETAgent",
+	Calculated:        "Calculated",
+	JMXAgent:          "JMXAgent",
+	SNMPTrap:          "SNMPTrap",
+	DependentItem:     "DependentItem",
+	HTTPAgent:         "HTTPAgent",
+	SNMPAgent:         "SNMPAgent",
+	Script:            "Script",
+}
+
+var valueTypeNames = map[ValueType]string{
+	Float:     "Float",
+	Character: "Character",
+	Log:       "Log",
+	Unsigned:  "Unsigned",
+	Text:      "Text",
+}
+
+func (t ItemType) String() string {
+	if s, ok := itemTypeNames[t]; ok {
+		return s
+	}
+	return fmt.Sprintf("ItemType(%d)", int(t))
+}
+
+func (t ValueType) String() string {
+	if s, ok := valueTypeNames[t]; ok {
+		return s
+	}
+	return fmt.Sprintf("ValueType(%d)", int(t))
+}
+
+// Columns of items CSV, in order.
+var itemCSVColumns = []string{"key", "name", "type", "value_type", "delay", "history", "trends"}
+
+// Writes items as CSV with header row and columns key, name, type, value_type, delay, history, trends.
+// Types and value types are written as names like "ZabbixTrapper" and "Float", delay with custom intervals.
+func (items Items) WriteCSV(w io.Writer) (err error) {
+	cw := csv.NewWriter(w)
+	err = cw.Write(itemCSVColumns)
+	if err != nil {
+		return
+	}
+
+	for _, item := range items {
+		valueType := item.ValueType
+		if vt, e := strconv.Atoi(item.ValueType); e == nil {
+			valueType = ValueType(vt).String()
+		}
+		err = cw.Write([]string{
+			item.Key, item.Name, item.Type.String(), valueType,
+			item.delayString(), string(item.History), string(item.Trends),
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// Reads items written by Items.WriteCSV for creation on given host.
+// Header row is required, columns may be in any order. Type names are case-insensitive.
+// Errors contain line number.
+func ReadItemsCSV(r io.Reader, hostId string) (res Items, err error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		return
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range itemCSVColumns {
+		if _, ok := columns[name]; !ok {
+			err = fmt.Errorf("Line 1: missing column %s.", name)
+			return
+		}
+	}
+
+	for {
+		var record []string
+		record, err = cr.Read()
+		if err == io.EOF {
+			err = nil
+			return
+		}
+		if err != nil {
+			return
+		}
+		line, _ := cr.FieldPos(0)
+
+		item := Item{
+			HostId:  hostId,
+			Key:     record[columns["key"]],
+			Name:    record[columns["name"]],
+			History: StoragePeriod(record[columns["history"]]),
+			Trends:  StoragePeriod(record[columns["trends"]]),
+		}
+
+		var ok bool
+		item.Type, ok = parseItemType(record[columns["type"]])
+		if !ok {
+			err = fmt.Errorf("Line %d: unknown type %q.", line, record[columns["type"]])
+			return
+		}
+
+		valueType, ok := parseValueType(record[columns["value_type"]])
+		if !ok {
+			err = fmt.Errorf("Line %d: unknown value type %q.", line, record[columns["value_type"]])
+			return
+		}
+		item.ValueType = strconv.Itoa(int(valueType))
+
+		// user macros are kept as is, like in Item.UnmarshalJSON
+		switch delay := record[columns["delay"]]; {
+		case strings.HasPrefix(delay, "{$"):
+			item.DelayRaw = delay
+		case delay != "":
+			item.Delay, item.DelayIntervals, err = parseDelay(delay)
+			if err != nil {
+				err = fmt.Errorf("Line %d: bad delay %q.", line, delay)
+				return
+			}
+		}
+
+		res = append(res, item)
+	}
+}
+
+func parseItemType(s string) (ItemType, bool) {
+	for t, name := range itemTypeNames {
+		if strings.EqualFold(name, strings.TrimSpace(s)) {
+			return t, true
+		}
+	}
+	return 0, false
+}
+
+func parseValueType(s string) (ValueType, bool) {
+	for t, name := range valueTypeNames {
+		if strings.EqualFold(name, strings.TrimSpace(s)) {
+			return t, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,78 @@
+package zabbix_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "."
+)
+
+func TestItemsCSV(t *testing.T) {
+	items := Items{
+		{HostId: "10084", Key: "key.lala.laa", Name: "name for key", Type: ZabbixTrapper, ValueType: "0", History: "90d", Trends: "0"},
+		{HostId: "10084", Key: `net.tcp.service[http,"my host",80]`, Name: `HTTP, "my host"`, Type: SimpleCheck, ValueType: "3", Delay: 60, History: "7", Trends: "365d"},
+		{HostId: "10084", Key: "system.cpu.load", Name: "Load", Type: ZabbixAgent, ValueType: "0", Delay: 60, History: "7d", Trends: "365d",
+			DelayIntervals: []DelayInterval{{Type: FlexibleInterval, Interval: "50s", Period: "1-5,09:00-18:00"}}},
+		{HostId: "10084", Key: "agent.ping", Name: "Ping", Type: ZabbixAgent, ValueType: "3", DelayRaw: "{$DELAY}", History: "7d", Trends: "365d"},
+	}
+
+	var buf bytes.Buffer
+	err := items.WriteCSV(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items2, err := ReadItemsCSV(&buf, "10084")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(items, items2) {
+		t.Errorf("Items are not equal:\n%#v\n%#v", items, items2)
+	}
+}
+
+func TestReadItemsCSVCaseInsensitive(t *testing.T) {
+	data := "key,name,type,value_type,delay,history,trends\nkey.a,A,zabbixagent,UNSIGNED,30,7d,365d\n"
+	items, err := ReadItemsCSV(strings.NewReader(data), "10084")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Type != ZabbixAgent || items[0].ValueType != "3" {
+		t.Errorf("Unexpected items: %#v", items)
+	}
+}
+
+func TestReadItemsCSVDelay(t *testing.T) {
+	data := "key,name,type,value_type,delay,history,trends\nkey.a,A,ZabbixAgent,Float,\"1m;50s/1-5,09:00-18:00\",7d,365d\n"
+	items, err := ReadItemsCSV(strings.NewReader(data), "10084")
+	if err != nil {
+		t.Fatal(err)
+	}
+	intervals := []DelayInterval{{Type: FlexibleInterval, Interval: "50s", Period: "1-5,09:00-18:00"}}
+	if len(items) != 1 || items[0].Delay != 60 || !reflect.DeepEqual(items[0].DelayIntervals, intervals) {
+		t.Fatalf("Unexpected items: %#v", items)
+	}
+
+	var buf bytes.Buffer
+	err = items.WriteCSV(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items2, err := ReadItemsCSV(&buf, "10084")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(items, items2) {
+		t.Errorf("Items are not equal:\n%#v\n%#v", items, items2)
+	}
+}
+
+func TestReadItemsCSVErrors(t *testing.T) {
+	data := "key,name,type,value_type,delay,history,trends\nkey.a,A,ZabbixAgent,Float,30,7d,365d\nkey.b,B,NoSuchType,Float,30,7d,365d\n"
+	_, err := ReadItemsCSV(strings.NewReader(data), "10084")
+	if err == nil || !strings.Contains(err.Error(), "Line 3") {
+		t.Errorf("Expected error with Line 3, got %v", err)
+	}
+}
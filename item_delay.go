@@ -0,0 +1,89 @@
+package zabbix
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type (
+	DelayIntervalType int
+)
+
+const (
+	FlexibleInterval   DelayIntervalType = 0
+	SchedulingInterval DelayIntervalType = 1
+)
+
+// Custom interval of item update: https://www.zabbix.com/documentation/3.4/manual/config/items/item/custom_intervals
+// Flexible interval sets Interval like "5m" during Period like "1-5,09:00-18:00".
+// Scheduling interval sets Interval like "wd1-5h9-18" and has no Period.
+type DelayInterval struct {
+	Type     DelayIntervalType
+	Interval string
+	Period   string
+}
+
+func (d DelayInterval) String() string {
+	if d.Type == FlexibleInterval {
+		return d.Interval + "/" + d.Period
+	}
+	return d.Interval
+}
+
+// Renders delay string like "30;5m/1-5,09:00-18:00" with simple interval first, as required by Zabbix.
+func renderDelay(delay int, intervals []DelayInterval) string {
+	parts := make([]string, len(intervals)+1)
+	parts[0] = strconv.Itoa(delay)
+	for i, d := range intervals {
+		parts[i+1] = d.String()
+	}
+	return strings.Join(parts, ";")
+}
+
+// Returns delay string as sent to Zabbix: DelayRaw if set, or simple interval with custom intervals.
+func (item Item) delayString() string {
+	if item.DelayRaw != "" {
+		return item.DelayRaw
+	}
+	return renderDelay(item.Delay, item.DelayIntervals)
+}
+
+// Parses delay string with optional time suffix and custom intervals. Simple interval is returned in seconds.
+func parseDelay(s string) (delay int, intervals []DelayInterval, err error) {
+	parts := strings.Split(s, ";")
+	delay, err = parseSeconds(parts[0])
+	if err != nil {
+		err = fmt.Errorf("Failed to parse delay %q.", s)
+		return
+	}
+
+	for _, p := range parts[1:] {
+		if p == "" {
+			continue
+		}
+		if i := strings.Index(p, "/"); i >= 0 {
+			intervals = append(intervals, DelayInterval{Type: FlexibleInterval, Interval: p[:i], Period: p[i+1:]})
+		} else {
+			intervals = append(intervals, DelayInterval{Type: SchedulingInterval, Interval: p})
+		}
+	}
+	return
+}
+
+// Parses number of seconds with optional time suffix, like "30", "30s" or "5m".
+func parseSeconds(s string) (n int, err error) {
+	s = strings.TrimSpace(s)
+	unit := 1
+	suffixes := map[byte]int{'s': 1, 'm': 60, 'h': 3600, 'd': 86400, 'w': 7 * 86400}
+	if s != "" {
+		if u, ok := suffixes[s[len(s)-1]]; ok {
+			unit = u
+			s = s[:len(s)-1]
+		}
+	}
+
+	n, err = strconv.Atoi(s)
+	n *= unit
+	return
+}
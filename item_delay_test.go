@@ -0,0 +1,88 @@
+package zabbix_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	. "."
+)
+
+func TestItemDelayIntervals(t *testing.T) {
+	item := Item{
+		Key:   "agent.ping",
+		Delay: 30,
+		DelayIntervals: []DelayInterval{
+			{Type: FlexibleInterval, Interval: "5m", Period: "1-5,09:00-18:00"},
+			{Type: SchedulingInterval, Interval: "wd1-5h9-18"},
+		},
+	}
+	b, err := json.Marshal(item)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]interface{}
+	if err = json.Unmarshal(b, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m["delay"] != "30;5m/1-5,09:00-18:00;wd1-5h9-18" {
+		t.Errorf("Unexpected delay %#v", m["delay"])
+	}
+
+	var item2 Item
+	if err = json.Unmarshal(b, &item2); err != nil {
+		t.Fatal(err)
+	}
+	if item2.Delay != 30 || !reflect.DeepEqual(item2.DelayIntervals, item.DelayIntervals) {
+		t.Errorf("Unexpected item: %#v", item2)
+	}
+
+	for data, expected := range map[string]int{`{"delay": 60}`: 60, `{"delay": "60"}`: 60, `{"delay": "1m"}`: 60, `{"delay": "1h;"}`: 3600} {
+		var item Item
+		if err = json.Unmarshal([]byte(data), &item); err != nil {
+			t.Fatalf("%s: %s", data, err)
+		}
+		if item.Delay != expected || item.DelayIntervals != nil {
+			t.Errorf("%s: unexpected item %#v", data, item)
+		}
+	}
+
+	// simple interval without custom intervals is sent as number
+	b, err = json.Marshal(Item{Delay: 60})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = json.Unmarshal(b, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m["delay"] != float64(60) {
+		t.Errorf("Unexpected delay %#v", m["delay"])
+	}
+
+	// user macro is kept as is and sent back
+	for _, data := range []string{`{"delay": "{$DELAY}"}`, `{"delay": "{$DELAY};50s/1-5,09:00-18:00"}`} {
+		var item Item
+		if err = json.Unmarshal([]byte(data), &item); err != nil {
+			t.Fatalf("%s: %s", data, err)
+		}
+		m = map[string]interface{}{}
+		if err = json.Unmarshal([]byte(data), &m); err != nil {
+			t.Fatal(err)
+		}
+		if item.Delay != 0 || item.DelayIntervals != nil || item.DelayRaw != m["delay"] {
+			t.Errorf("%s: unexpected item %#v", data, item)
+		}
+
+		b, err = json.Marshal(item)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sent := map[string]interface{}{}
+		if err = json.Unmarshal(b, &sent); err != nil {
+			t.Fatal(err)
+		}
+		if sent["delay"] != m["delay"] {
+			t.Errorf("%s: unexpected sent delay %#v", data, sent["delay"])
+		}
+	}
+}
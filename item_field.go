@@ -0,0 +1,68 @@
+package zabbix
+
+import (
+	"fmt"
+)
+
+// Item field name for output parameter of item.get.
+type ItemField string
+
+const (
+	ItemFieldItemId      ItemField = "itemid"
+	ItemFieldDelay       ItemField = "delay"
+	ItemFieldHostId      ItemField = "hostid"
+	ItemFieldInterfaceId ItemField = "interfaceid"
+	ItemFieldTemplateId  ItemField = "templateid"
+	ItemFieldKey         ItemField = "key_"
+	ItemFieldName        ItemField = "name"
+	ItemFieldType        ItemField = "type"
+	ItemFieldStatus      ItemField = "status"
+	ItemFieldValueType   ItemField = "value_type"
+	ItemFieldLastValue   ItemField = "lastvalue"
+	ItemFieldLastClock   ItemField = "lastclock"
+	ItemFieldLastNs      ItemField = "lastns"
+	ItemFieldPrevValue   ItemField = "prevvalue"
+	ItemFieldNextCheck   ItemField = "nextcheck"
+	ItemFieldDataType    ItemField = "data_type"
+	ItemFieldDelta       ItemField = "delta"
+	ItemFieldDescription ItemField = "description"
+	ItemFieldError       ItemField = "error"
+	ItemFieldHistory     ItemField = "history"
+	ItemFieldTrends      ItemField = "trends"
+	ItemFieldFlags       ItemField = "flags"
+	ItemFieldAuthType    ItemField = "authtype"
+	ItemFieldUsername    ItemField = "username"
+	ItemFieldPublicKey   ItemField = "publickey"
+	ItemFieldParams      ItemField = "params"
+)
+
+var itemFields = map[ItemField]bool{
+	ItemFieldItemId: true, ItemFieldDelay: true, ItemFieldHostId: true, ItemFieldInterfaceId: true,
+	ItemFieldTemplateId: true, ItemFieldKey: true, ItemFieldName: true, ItemFieldType: true,
+	ItemFieldStatus: true, ItemFieldValueType: true, ItemFieldLastValue: true, ItemFieldDataType: true,
+	ItemFieldDelta: true, ItemFieldDescription: true, ItemFieldError: true, ItemFieldHistory: true,
+	ItemFieldTrends: true, ItemFieldFlags: true, ItemFieldAuthType: true, ItemFieldUsername: true,
+	ItemFieldPublicKey: true, ItemFieldParams: true, ItemFieldLastClock: true, ItemFieldLastNs: true,
+	ItemFieldPrevValue: true, ItemFieldNextCheck: true,
+}
+
+// Gets items with only given fields in output. Unknown fields are rejected before calling API.
+// All fields are returned if none are given.
+func (api *API) ItemsGetFields(params Params, fields ...ItemField) (res Items, err error) {
+	if len(fields) == 0 {
+		return api.ItemsGet(params)
+	}
+
+	output := make([]string, len(fields))
+	for i, f := range fields {
+		if !itemFields[f] {
+			err = fmt.Errorf("Unknown item field %q.", string(f))
+			return
+		}
+		output[i] = string(f)
+	}
+
+	params = params.Clone()
+	params["output"] = output
+	return api.ItemsGet(params)
+}
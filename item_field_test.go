@@ -0,0 +1,46 @@
+package zabbix_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "."
+)
+
+func TestItemsGetFields(t *testing.T) {
+	var calls int
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		calls++
+		var params struct {
+			Output  []string `json:"output"`
+			HostIds string   `json:"hostids"`
+		}
+		call.decodeParams(&params, t)
+		expected := []string{"itemid", "key_", "name"}
+		if !reflect.DeepEqual(params.Output, expected) || params.HostIds != "42" {
+			t.Errorf("Unexpected params: %#v", params)
+		}
+		return []map[string]string{{"itemid": "23", "key_": "agent.ping", "name": "Ping"}}
+	})
+	defer server.Close()
+
+	params := Params{"hostids": "42"}
+	items, err := api.ItemsGetFields(params, ItemFieldItemId, ItemFieldKey, ItemFieldName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Key != "agent.ping" {
+		t.Errorf("Unexpected items: %#v", items)
+	}
+	if _, present := params["output"]; present {
+		t.Errorf("params are changed: %#v", params)
+	}
+
+	_, err = api.ItemsGetFields(params, ItemFieldKey, ItemField("keys"))
+	if err == nil {
+		t.Error("Expected error for unknown field")
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 call, got %d", calls)
+	}
+}
@@ -0,0 +1,27 @@
+package zabbix
+
+import (
+	"strings"
+)
+
+// Builds item key like `net.tcp.service[http,"my host",80]` from name and parameters.
+// Parameters which are empty or contain special characters (quotes, commas, brackets, spaces) are quoted,
+// embedded double quotes are escaped. Key without parameters has no brackets.
+func BuildItemKey(name string, params ...string) string {
+	if len(params) == 0 {
+		return name
+	}
+
+	quoted := make([]string, len(params))
+	for i, p := range params {
+		quoted[i] = quoteKeyParam(p)
+	}
+	return name + "[" + strings.Join(quoted, ",") + "]"
+}
+
+func quoteKeyParam(p string) string {
+	if p != "" && !strings.ContainsAny(p, "\",[] ") {
+		return p
+	}
+	return `"` + strings.Replace(p, `"`, `\"`, -1) + `"`
+}
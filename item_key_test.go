@@ -0,0 +1,28 @@
+package zabbix_test
+
+import (
+	"testing"
+
+	. "."
+)
+
+func TestBuildItemKey(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		params   []string
+		expected string
+	}{
+		{"agent.ping", nil, "agent.ping"},
+		{"vfs.fs.size", []string{"/", "pfree"}, "vfs.fs.size[/,pfree]"},
+		{"net.tcp.service", []string{"http", "my host", "80"}, `net.tcp.service[http,"my host",80]`},
+		{"system.run", []string{`echo "a,b"`}, `system.run["echo \"a,b\""]`},
+		{"log", []string{"/var/log/app.log", "", "UTF-8"}, `log[/var/log/app.log,"",UTF-8]`},
+		{"vfs.dev.read", []string{"[sda]", "ops]"}, `vfs.dev.read["[sda]","ops]"]`},
+		{"web.page.get", []string{"example.com", "/a=1,b=2"}, `web.page.get[example.com,"/a=1,b=2"]`},
+	} {
+		actual := BuildItemKey(c.name, c.params...)
+		if actual != c.expected {
+			t.Errorf("%s %q: expected %s, got %s", c.name, c.params, c.expected, actual)
+		}
+	}
+}
@@ -1,7 +1,14 @@
 package zabbix_test
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	. "."
 )
@@ -51,3 +58,927 @@ func TestItems(t *testing.T) {
 	item := CreateItem(app, t)
 	DeleteItem(item, t)
 }
+
+func TestItemsGetChangedSince(t *testing.T) {
+	var calls int
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		calls++
+		if call.Method != "item.get" {
+			t.Errorf("Unexpected method %s", call.Method)
+		}
+		var params map[string]interface{}
+		call.decodeParams(&params, t)
+		for _, key := range []string{"filter", "search", "time_from", "lastclock", "limit"} {
+			if _, ok := params[key]; ok {
+				t.Errorf("Unexpected %s in params: %#v", key, params)
+			}
+		}
+		// lastclock is not a modification time, so items with old and new values are returned alike
+		return []map[string]string{
+			{"itemid": "1", "key_": "key.lala.laa", "lastclock": "0"},
+			{"itemid": "2", "key_": "key.old", "lastclock": "1400000000"},
+			{"itemid": "3", "key_": "key.new", "lastclock": strconv.FormatInt(time.Now().Unix(), 10)},
+		}
+	})
+	defer server.Close()
+
+	for _, since := range []time.Time{{}, time.Now().Add(-time.Hour), time.Now().Add(time.Hour)} {
+		calls = 0
+		items, err := api.ItemsGetChangedSince(since)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if calls != 1 || len(items) != 3 || items[0].ItemId != "1" || items[2].ItemId != "3" {
+			t.Errorf("%v: expected all items with one call, got %d calls: %#v", since, calls, items)
+		}
+	}
+}
+
+func TestItemsUpsert(t *testing.T) {
+	var updated, created []map[string]interface{}
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		switch call.Method {
+		case "item.get":
+			var params map[string]interface{}
+			call.decodeParams(&params, t)
+			if !reflect.DeepEqual(params["hostids"], []interface{}{"10084"}) {
+				t.Errorf("Unexpected hostids: %#v", params["hostids"])
+			}
+			if !reflect.DeepEqual(params["filter"], map[string]interface{}{"key_": []interface{}{"key.old", "key.new"}}) {
+				t.Errorf("Unexpected filter: %#v", params["filter"])
+			}
+			return []map[string]string{{"itemid": "23", "hostid": "10084", "key_": "key.old"}}
+		case "item.update":
+			call.decodeParams(&updated, t)
+			return map[string]interface{}{"itemids": []string{"23"}}
+		case "item.create":
+			call.decodeParams(&created, t)
+			return map[string]interface{}{"itemids": []string{"24"}}
+		}
+		t.Errorf("Unexpected method %s", call.Method)
+		return nil
+	})
+	defer server.Close()
+
+	items := Items{
+		{HostId: "10084", Key: "key.old", Name: "old", Type: ZabbixTrapper, ValueType: "0"},
+		{HostId: "10084", Key: "key.new", Name: "new", Type: ZabbixTrapper, ValueType: "0"},
+	}
+	err := api.ItemsUpsert(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(updated) != 1 || updated[0]["itemid"] != "23" || updated[0]["key_"] != "key.old" {
+		t.Errorf("Unexpected update: %#v", updated)
+	}
+	if len(created) != 1 || created[0]["itemid"] != nil || created[0]["key_"] != "key.new" {
+		t.Errorf("Unexpected create: %#v", created)
+	}
+	if items[0].ItemId != "23" || items[1].ItemId != "24" {
+		t.Errorf("Unexpected ids: %#v", items)
+	}
+}
+
+func TestItemStoragePeriod(t *testing.T) {
+	for data, expected := range map[string][2]int{
+		`{"history": "90d", "trends": "0"}`:   {90, 0},
+		`{"history": "2w", "trends": "365d"}`: {14, 365},
+		`{"history": 7, "trends": "365"}`:     {7, 365},
+	} {
+		var item Item
+		err := json.Unmarshal([]byte(data), &item)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		history, err := item.History.Days()
+		if err != nil {
+			t.Fatal(err)
+		}
+		trends, err := item.Trends.Days()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if history != expected[0] || trends != expected[1] {
+			t.Errorf("%s: expected %v, got %d and %d", data, expected, history, trends)
+		}
+	}
+
+	if _, err := StoragePeriod("{$HISTORY}").Days(); err == nil {
+		t.Error("Expected error for macro")
+	}
+}
+
+func TestItemsCreateWithApplications(t *testing.T) {
+	api := getAPI(t)
+
+	group := CreateHostGroup(t)
+	defer DeleteHostGroup(group, t)
+
+	host := CreateHost(group, t)
+	defer DeleteHost(host, t)
+
+	app1 := CreateApplication(host, t)
+	defer DeleteApplication(app1, t)
+	app2 := CreateApplication(host, t)
+	defer DeleteApplication(app2, t)
+
+	items := Items{{
+		HostId:         host.HostId,
+		Key:            "key.apps",
+		Name:           "name for key",
+		Type:           ZabbixTrapper,
+		ValueType:      "0",
+		ApplicationIds: []string{app1.ApplicationId, app2.ApplicationId},
+	}}
+	err := api.ItemsCreate(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer DeleteItem(&items[0], t)
+
+	for _, app := range []*Application{app1, app2} {
+		found, err := api.ItemsGet(Params{"applicationids": app.ApplicationId, "output": []string{"itemid", "key_"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(found) != 1 || found[0].ItemId != items[0].ItemId {
+			t.Errorf("Bad items for application %s: %#v", app.ApplicationId, found)
+		}
+	}
+}
+
+func TestItemsCreateWithApplicationsUnsupported(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		if call.Method != "apiinfo.version" && call.Method != "APIInfo.version" {
+			t.Errorf("Unexpected method %s", call.Method)
+		}
+		return "5.4.0"
+	})
+	defer server.Close()
+
+	err := api.ItemsCreate(Items{{HostId: "10084", Key: "key.apps", ApplicationIds: []string{"1", "2"}}})
+	if err == nil {
+		t.Fatal("Expected error")
+	}
+}
+
+func TestItemsGetByGroupIds(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		var params map[string]interface{}
+		call.decodeParams(&params, t)
+		if !reflect.DeepEqual(params["groupids"], []interface{}{"1", "2", "3"}) {
+			t.Errorf("Unexpected groupids: %#v", params["groupids"])
+		}
+		return []interface{}{}
+	})
+	defer server.Close()
+
+	_, err := api.ItemsGetByGroupIds([]string{"1", "2", "3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = api.ItemsGetByGroupIds(nil)
+	if err != ErrEmptyIds {
+		t.Errorf("Expected ErrEmptyIds, got %v", err)
+	}
+}
+
+func TestItemFlags(t *testing.T) {
+	for data, expected := range map[string]ItemFlag{`{"flags": "0"}`: PlainItem, `{"flags": "2"}`: PrototypeItem, `{"flags": "4"}`: DiscoveredItem} {
+		var item Item
+		err := json.Unmarshal([]byte(data), &item)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if item.Flags != expected {
+			t.Errorf("%s: expected %d, got %d", data, expected, item.Flags)
+		}
+	}
+
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		if call.Method != "item.get" {
+			t.Errorf("Unexpected method %s", call.Method)
+		}
+		var params map[string]interface{}
+		call.decodeParams(&params, t)
+		if params["hostids"] != "10084" || !reflect.DeepEqual(params["filter"], map[string]interface{}{"flags": float64(4)}) {
+			t.Errorf("Unexpected params: %#v", params)
+		}
+		return []interface{}{map[string]interface{}{"itemid": "23", "key_": "vfs.fs.size[/,free]", "flags": "4"}}
+	})
+	defer server.Close()
+
+	items, err := api.ItemsGetDiscovered("10084")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Flags != DiscoveredItem {
+		t.Fatalf("Unexpected items: %#v", items)
+	}
+
+	err = api.ItemsUpdate(items)
+	if err == nil {
+		t.Error("Expected error for discovered item update")
+	}
+}
+
+func TestItemsCreateOnInterface(t *testing.T) {
+	var created []map[string]interface{}
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		var params map[string]interface{}
+		switch call.Method {
+		case "hostinterface.get":
+			call.decodeParams(&params, t)
+			if params["hostids"] != "10084" || !reflect.DeepEqual(params["filter"], map[string]interface{}{"type": float64(1)}) {
+				t.Errorf("Unexpected params: %#v", params)
+			}
+			return []map[string]string{
+				{"interfaceid": "30", "hostid": "10084", "type": "1", "main": "0", "ip": "10.0.0.2", "port": "10050"},
+				{"interfaceid": "31", "hostid": "10084", "type": "1", "main": "1", "ip": "10.0.0.1", "port": "10050"},
+			}
+		case "item.create":
+			call.decodeParams(&created, t)
+			return map[string]interface{}{"itemids": []string{"23", "24"}}
+		}
+		t.Errorf("Unexpected method %s", call.Method)
+		return nil
+	})
+	defer server.Close()
+
+	items := Items{
+		{Key: "agent.ping", Name: "Ping", Type: ZabbixAgent, ValueType: "3", Delay: 60},
+		{Key: "agent.version", Name: "Version", Type: ZabbixAgent, ValueType: "1", Delay: 3600},
+	}
+	err := api.ItemsCreateOnInterface(items, "10084", Agent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, item := range created {
+		if item["hostid"] != "10084" || item["interfaceid"] != "31" {
+			t.Errorf("Bad item %d: %#v", i, item)
+		}
+	}
+	if len(created) != 2 || items[0].InterfaceId != "31" || items[1].ItemId != "24" {
+		t.Errorf("Bad items: %#v", items)
+	}
+}
+
+func TestItemsCreateOnMissingInterface(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		if call.Method != "hostinterface.get" {
+			t.Errorf("Unexpected method %s", call.Method)
+		}
+		return []interface{}{}
+	})
+	defer server.Close()
+
+	err := api.ItemsCreateOnInterface(Items{{Key: "ifInOctets"}}, "10084", SNMP)
+	if err == nil {
+		t.Error("Expected error")
+	}
+}
+
+func TestItemsGetByTemplateAndGroupId(t *testing.T) {
+	var params map[string]interface{}
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		params = nil
+		call.decodeParams(&params, t)
+		return []interface{}{}
+	})
+	defer server.Close()
+
+	_, err := api.ItemsGetByTemplateId("10001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params["templateids"] != "10001" || params["groupids"] != nil {
+		t.Errorf("Unexpected params: %#v", params)
+	}
+
+	_, err = api.ItemsGetByGroupId("2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(params["groupids"], []interface{}{"2"}) || params["templateids"] != nil {
+		t.Errorf("Unexpected params: %#v", params)
+	}
+}
+
+func TestItemsCreateSkipExisting(t *testing.T) {
+	for _, race := range []bool{false, true} {
+		var methods []string
+		api, server := newMockAPI(t, func(call *mockCall) interface{} {
+			methods = append(methods, call.Method)
+			switch {
+			case call.Method == "item.get" && race && len(methods) == 1:
+				return []interface{}{}
+			case call.Method == "item.get":
+				return []map[string]string{{"itemid": "23", "hostid": "10084", "key_": "key.lala.laa"}}
+			case call.Method == "item.create":
+				return &Error{Code: -32602, Message: "Invalid params.", Data: `Item with key "key.lala.laa" already exists on "host".`}
+			}
+			t.Errorf("Unexpected method %s", call.Method)
+			return nil
+		})
+		api.SkipExistingItems = true
+
+		items := Items{{HostId: "10084", Key: "key.lala.laa", Name: "name for key", Type: ZabbixTrapper, ValueType: "0"}}
+		err := api.ItemsCreate(items)
+		server.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if items[0].ItemId != "23" {
+			t.Errorf("Id is not set: %#v", items[0])
+		}
+
+		expected := []string{"item.get"}
+		if race {
+			expected = []string{"item.get", "item.create", "item.get"}
+		}
+		if !reflect.DeepEqual(methods, expected) {
+			t.Errorf("Expected %v, got %v", expected, methods)
+		}
+	}
+}
+
+func TestWaitForItemValue(t *testing.T) {
+	var polls int
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		var params map[string]interface{}
+		call.decodeParams(&params, t)
+		if params["itemids"] == "404" {
+			return []interface{}{}
+		}
+		polls++
+		if polls < 3 {
+			return []map[string]string{{"itemid": "23", "lastclock": "0", "lastvalue": ""}}
+		}
+		return []map[string]string{{"itemid": "23", "lastclock": "1400000000", "lastvalue": "0"}}
+	})
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	value, err := api.WaitForItemValue(ctx, "23", time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != "0" || polls != 3 {
+		t.Errorf("Expected value 0 on third poll, got %q on poll %d", value, polls)
+	}
+
+	_, err = api.WaitForItemValue(ctx, "404", time.Millisecond)
+	if _, ok := err.(*ExpectedOneResult); !ok {
+		t.Errorf("Expected *ExpectedOneResult for missing item, got %#v", err)
+	}
+
+	polls = 0
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = api.WaitForItemValue(ctx, "23", 50*time.Millisecond)
+	if err != context.DeadlineExceeded {
+		t.Errorf("Expected deadline error, got %#v", err)
+	}
+}
+
+func TestItemsGetIncludingWeb(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		var params map[string]interface{}
+		call.decodeParams(&params, t)
+		if params["webitems"] != true || params["hostids"] != "10084" {
+			t.Errorf("Unexpected params: %#v", params)
+		}
+		return []interface{}{
+			map[string]interface{}{"itemid": "23", "key_": "agent.ping", "type": 0},
+			map[string]interface{}{"itemid": "24", "key_": "web.test.in[Site,,bps]", "type": 9},
+		}
+	})
+	defer server.Close()
+
+	params := Params{"hostids": "10084"}
+	items, err := api.ItemsGetIncludingWeb(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var web Items
+	for _, item := range items {
+		if item.Type == WebItem {
+			web = append(web, item)
+		}
+	}
+	if len(web) != 1 || web[0].ItemId != "24" {
+		t.Errorf("Unexpected web items: %#v", web)
+	}
+	if _, present := params["webitems"]; present {
+		t.Errorf("params are changed: %#v", params)
+	}
+}
+
+func TestItemsCountByType(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		var params map[string]interface{}
+		call.decodeParams(&params, t)
+		if !reflect.DeepEqual(params["output"], []interface{}{"type"}) || params["hostids"] != "10084" {
+			t.Errorf("Unexpected params: %#v", params)
+		}
+		if params["search"] != nil {
+			return &Error{Code: -32602, Message: "Invalid params.", Data: `Invalid parameter "/search": an array is expected.`}
+		}
+		res := make([]map[string]string, 0, 2000)
+		for i := 0; i < 1000; i++ {
+			res = append(res, map[string]string{"type": "0"})
+		}
+		for i := 0; i < 999; i++ {
+			res = append(res, map[string]string{"type": "7"})
+		}
+		return append(res, map[string]string{"type": "2"})
+	})
+	defer server.Close()
+
+	counts, err := api.ItemsCountByType(Params{"hostids": "10084"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[ItemType]int{ZabbixAgent: 1000, ZabbixAgentActive: 999, ZabbixTrapper: 1}
+	if !reflect.DeepEqual(counts, expected) {
+		t.Errorf("Expected %v, got %v", expected, counts)
+	}
+
+	_, err = api.ItemsCountByType(Params{"hostids": "10084", "search": "a"})
+	if _, ok := err.(*Error); !ok {
+		t.Errorf("Expected API error, got %#v", err)
+	}
+}
+
+func TestItemsGetMap(t *testing.T) {
+	var empty bool
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		var params map[string]interface{}
+		call.decodeParams(&params, t)
+		if params["preservekeys"] != true || params["output"] != "extend" {
+			t.Errorf("Unexpected params: %#v", params)
+		}
+		if empty {
+			return []interface{}{}
+		}
+		return map[string]interface{}{
+			"23": map[string]string{"itemid": "23", "key_": "agent.ping"},
+			"24": map[string]string{"itemid": "24", "key_": "system.uptime"},
+		}
+	})
+	defer server.Close()
+
+	items, err := api.ItemsGetMap(Params{"hostids": "10084"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || items["23"].Key != "agent.ping" || items["24"].ItemId != "24" {
+		t.Errorf("Unexpected items: %#v", items)
+	}
+
+	empty = true
+	items, err = api.ItemsGetMap(Params{"hostids": "10085"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if items == nil || len(items) != 0 {
+		t.Errorf("Unexpected items: %#v", items)
+	}
+}
+
+func TestItemsGetByApplicationIdTags(t *testing.T) {
+	for version, tags := range map[string]bool{"5.2.7": false, "5.4.0": true} {
+		api, server := newMockAPI(t, func(call *mockCall) interface{} {
+			switch call.Method {
+			case "apiinfo.version", "APIInfo.version":
+				return version
+			case "item.get":
+				var params map[string]interface{}
+				call.decodeParams(&params, t)
+				if tags {
+					expected := []interface{}{map[string]interface{}{"tag": "Application", "value": "CPU", "operator": float64(1)}}
+					if !reflect.DeepEqual(params["tags"], expected) || params["applicationids"] != nil {
+						t.Errorf("%s: unexpected params: %#v", version, params)
+					}
+				} else if !reflect.DeepEqual(params["applicationids"], []interface{}{"CPU"}) || params["tags"] != nil {
+					t.Errorf("%s: unexpected params: %#v", version, params)
+				}
+				return []map[string]string{{"itemid": "23", "key_": "system.cpu.load"}}
+			}
+			t.Errorf("%s: unexpected method %s", version, call.Method)
+			return nil
+		})
+		api.ItemGroupingMode = AutoGrouping
+
+		items, err := api.ItemsGetByApplicationId("CPU")
+		server.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(items) != 1 {
+			t.Errorf("%s: unexpected items: %#v", version, items)
+		}
+	}
+}
+
+func TestItemsGetByTag(t *testing.T) {
+	for version, supported := range map[string]bool{"5.2.7": false, "5.4.0": true} {
+		api, server := newMockAPI(t, func(call *mockCall) interface{} {
+			switch call.Method {
+			case "apiinfo.version", "APIInfo.version":
+				return version
+			case "item.get":
+				var params map[string]interface{}
+				call.decodeParams(&params, t)
+				expected := []interface{}{map[string]interface{}{"tag": "component", "value": "cpu", "operator": float64(1)}}
+				if !reflect.DeepEqual(params["tags"], expected) || params["evaltype"] != float64(0) || params["hostids"] != "10084" || params["selectTags"] != "extend" {
+					t.Errorf("%s: unexpected params: %#v", version, params)
+				}
+				return []interface{}{map[string]interface{}{
+					"itemid": "23", "key_": "system.cpu.load", "tags": []map[string]string{{"tag": "component", "value": "cpu"}},
+				}}
+			}
+			t.Errorf("%s: unexpected method %s", version, call.Method)
+			return nil
+		})
+
+		items, err := api.ItemsGetByTag("10084", "component", "cpu")
+		server.Close()
+		if !supported {
+			if err == nil {
+				t.Errorf("%s: expected error, got %#v", version, items)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(items) != 1 || !reflect.DeepEqual(items[0].Tags, Tags{{Tag: "component", Value: "cpu"}}) {
+			t.Errorf("%s: unexpected items: %#v", version, items)
+		}
+	}
+}
+
+func TestItemsUpdateTemplated(t *testing.T) {
+	var calls int
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		calls++
+		return map[string]interface{}{"itemids": []string{"23"}}
+	})
+	defer server.Close()
+
+	items := Items{{ItemId: "23", TemplateId: "13", Key: "agent.ping", Name: "Ping"}}
+	err := api.ItemsUpdate(items)
+	if !errors.Is(err, ErrTemplatedItem) || calls != 0 {
+		t.Errorf("Expected ErrTemplatedItem, got %#v after %d calls", err, calls)
+	}
+
+	api.UpdateTemplatedItems = true
+	err = api.ItemsUpdate(items)
+	if err != nil || calls != 1 {
+		t.Errorf("Expected update, got %#v after %d calls", err, calls)
+	}
+
+	api.UpdateTemplatedItems = false
+	items[0].TemplateId = "0"
+	err = api.ItemsUpdate(items)
+	if err != nil || calls != 2 {
+		t.Errorf("Expected update, got %#v after %d calls", err, calls)
+	}
+}
+
+func TestItemHasValue(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		if call.Method != "item.get" {
+			t.Errorf("Unexpected method %s", call.Method)
+			return nil
+		}
+		return []map[string]string{
+			{"itemid": "23", "key_": "trap.zero", "lastvalue": "0", "lastclock": "1400000000", "lastns": "500"},
+			{"itemid": "24", "key_": "trap.never", "lastvalue": "0", "lastclock": "0", "lastns": "0"},
+			{"itemid": "25", "key_": "trap.output", "lastvalue": ""},
+		}
+	})
+	defer server.Close()
+
+	items, err := api.ItemsGet(Params{"hostids": "10084"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !items[0].HasValue() || !items[0].LastClock.Equal(time.Unix(1400000000, 500)) {
+		t.Errorf("Expected real zero value: %#v", items[0])
+	}
+	if items[1].HasValue() || items[2].HasValue() {
+		t.Errorf("Expected no values: %#v", items[1:])
+	}
+}
+
+func TestItemPrevValueNextCheck(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		return []map[string]string{
+			{"itemid": "23", "lastvalue": "0.42", "prevvalue": "0.40", "lastclock": "1400000000", "nextcheck": "1400000060"},
+			{"itemid": "24", "lastvalue": "0", "prevvalue": "0", "lastclock": "0", "nextcheck": "0"},
+			{"itemid": "25", "prevvalue": "7"},
+			{"itemid": "26", "lastvalue": "", "prevvalue": ""},
+		}
+	})
+	defer server.Close()
+
+	items, err := api.ItemsGet(Params{"output": []string{"itemid", "lastvalue", "prevvalue", "lastclock", "nextcheck"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if items[0].LastValue != "0.42" || items[0].PrevValue != "0.40" || !items[0].NextCheck.Equal(time.Unix(1400000060, 0)) {
+		t.Errorf("Unexpected item: %#v", items[0])
+	}
+	for _, item := range items[1:] {
+		if item.PrevValue != "" || !item.NextCheck.IsZero() {
+			t.Errorf("Unexpected item: %#v", item)
+		}
+	}
+}
+
+func TestItemsRenameKeys(t *testing.T) {
+	var updates []map[string]interface{}
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		switch call.Method {
+		case "item.get":
+			var params map[string]interface{}
+			call.decodeParams(&params, t)
+			if params["hostids"] != "10084" {
+				t.Errorf("Unexpected params: %#v", params)
+			}
+			return []interface{}{
+				map[string]interface{}{"itemid": "23", "key_": "cpu.load", "templateid": "0", "flags": "0"},
+				map[string]interface{}{"itemid": "24", "key_": "mem.free", "templateid": "0", "flags": "0"},
+				map[string]interface{}{"itemid": "25", "key_": "disk.free", "templateid": "0", "flags": "0"},
+				map[string]interface{}{"itemid": "26", "key_": "net.in", "templateid": "0", "flags": "0"},
+			}
+		case "item.update":
+			call.decodeParams(&updates, t)
+			return map[string]interface{}{"itemids": []string{"23", "24"}}
+		}
+		t.Errorf("Unexpected method %s", call.Method)
+		return nil
+	})
+	defer server.Close()
+
+	err := api.ItemsRenameKeys("10084", map[string]string{"mem.free": "vm.memory.size[free]", "cpu.load": "system.cpu.load"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []map[string]interface{}{
+		{"itemid": "23", "key_": "system.cpu.load"},
+		{"itemid": "24", "key_": "vm.memory.size[free]"},
+	}
+	if !reflect.DeepEqual(updates, expected) {
+		t.Errorf("Unexpected updates: %#v", updates)
+	}
+
+	updates = nil
+	err = api.ItemsRenameKeys("10084", map[string]string{"disk.free": "net.in"})
+	if err == nil || !strings.Contains(err.Error(), "already has item 26") {
+		t.Errorf("Expected error for existing key, got %v", err)
+	}
+	err = api.ItemsRenameKeys("10084", map[string]string{"disk.free": "net.in", "cpu.load": "cpu.load", "mem.free": "net.in"})
+	if err == nil || !strings.Contains(err.Error(), "disk.free and mem.free to the same key net.in") {
+		t.Errorf("Expected error for duplicate new key, got %v", err)
+	}
+	err = api.ItemsRenameKeys("10084", map[string]string{"no.such.key": "other.key"})
+	if err == nil {
+		t.Error("Expected error for missing key")
+	}
+	if updates != nil {
+		t.Errorf("Unexpected updates: %#v", updates)
+	}
+
+	// swap and self-mapping
+	err = api.ItemsRenameKeys("10084", map[string]string{"cpu.load": "mem.free", "mem.free": "cpu.load", "net.in": "net.in"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = []map[string]interface{}{
+		{"itemid": "23", "key_": "mem.free"},
+		{"itemid": "24", "key_": "cpu.load"},
+	}
+	if !reflect.DeepEqual(updates, expected) {
+		t.Errorf("Unexpected updates: %#v", updates)
+	}
+}
+
+func TestItemsCreateOnTemplate(t *testing.T) {
+	var created []map[string]interface{}
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		switch call.Method {
+		case "template.get":
+			var params map[string]interface{}
+			call.decodeParams(&params, t)
+			if params["templateids"] == "10001" {
+				return []map[string]string{{"templateid": "10001"}}
+			}
+			return []map[string]string{}
+		case "item.create":
+			call.decodeParams(&created, t)
+			return map[string]interface{}{"itemids": []string{"23"}}
+		}
+		t.Errorf("Unexpected method %s", call.Method)
+		return nil
+	})
+	defer server.Close()
+
+	items := Items{{Key: "agent.ping", Name: "Ping", Type: ZabbixAgent, ValueType: "3", Delay: 60, HostId: "10084", InterfaceId: "30"}}
+	err := api.ItemsCreateOnTemplate("10001", items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if items[0].ItemId != "23" || items[0].HostId != "10001" {
+		t.Errorf("Unexpected items: %#v", items)
+	}
+	if len(created) != 1 || created[0]["hostid"] != "10001" {
+		t.Fatalf("Unexpected params: %#v", created)
+	}
+	if _, present := created[0]["interfaceid"]; present {
+		t.Errorf("Unexpected interfaceid: %#v", created[0])
+	}
+
+	created = nil
+	err = api.ItemsCreateOnTemplate("10084", Items{{Key: "agent.ping", Name: "Ping", Type: ZabbixAgent, ValueType: "3", Delay: 60}})
+	if err == nil || created != nil {
+		t.Errorf("Expected error for host Id, got %v", err)
+	}
+}
+
+func TestItemsGetWithInterface(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		var params map[string]interface{}
+		call.decodeParams(&params, t)
+		if call.Method != "item.get" || params["selectInterfaces"] != "extend" {
+			t.Errorf("Unexpected call %s: %#v", call.Method, params)
+		}
+		return []interface{}{
+			map[string]interface{}{"itemid": "23", "key_": "agent.ping", "interfaceid": "30", "interfaces": []map[string]string{
+				{"interfaceid": "30", "hostid": "10084", "ip": "10.0.0.1", "dns": "", "port": "10050", "type": "1", "main": "1", "useip": "1"},
+			}},
+			map[string]interface{}{"itemid": "24", "key_": "agent.version", "interfaceid": "0", "interfaces": []interface{}{}},
+		}
+	})
+	defer server.Close()
+
+	items, err := api.ItemsGetWithInterface(Params{"hostids": "10084"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &HostInterface{InterfaceId: "30", HostId: "10084", IP: "10.0.0.1", Port: "10050", Type: Agent, Main: 1, UseIP: 1}
+	if len(items) != 2 || !reflect.DeepEqual(items[0].Interface, expected) {
+		t.Fatalf("Unexpected items: %#v", items)
+	}
+	if items[1].Interface != nil {
+		t.Errorf("Expected no interface for active item: %#v", items[1].Interface)
+	}
+
+	var item Item
+	err = json.Unmarshal([]byte(`{"itemid": "25", "interfaces": ["30"]}`), &item)
+	if err == nil || item.Interface != nil {
+		t.Errorf("Expected error for bad interfaces, got %v: %#v", err, item.Interface)
+	}
+}
+
+func TestResolveItemOrigin(t *testing.T) {
+	items := map[string]map[string]string{
+		"23": {"itemid": "23", "hostid": "10084", "templateid": "22", "key_": "agent.ping"}, // host
+		"22": {"itemid": "22", "hostid": "10001", "templateid": "21", "key_": "agent.ping"}, // Template OS Linux
+		"21": {"itemid": "21", "hostid": "10000", "templateid": "0", "key_": "agent.ping"},  // Template Agent
+		"31": {"itemid": "31", "hostid": "10084", "templateid": "32", "key_": "broken"},
+		"32": {"itemid": "32", "hostid": "10001", "templateid": "31", "key_": "broken"},
+		"41": {"itemid": "41", "hostid": "10084", "templateid": "0", "key_": "own"},
+	}
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		var params map[string]interface{}
+		call.decodeParams(&params, t)
+		switch call.Method {
+		case "item.get":
+			return []map[string]string{items[params["itemids"].(string)]}
+		case "template.get":
+			if params["templateids"] == "10000" {
+				return []map[string]string{{"templateid": "10000", "host": "Template Agent"}}
+			}
+			return []map[string]string{}
+		}
+		t.Errorf("Unexpected method %s", call.Method)
+		return nil
+	})
+	defer server.Close()
+
+	item, template, err := api.ResolveItemOrigin("23")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.ItemId != "21" || template.TemplateId != "10000" || template.Host != "Template Agent" {
+		t.Errorf("Unexpected origin: %#v, %#v", item, template)
+	}
+
+	_, _, err = api.ResolveItemOrigin("31")
+	if err == nil || !strings.Contains(err.Error(), "cyclic") {
+		t.Errorf("Expected cycle error, got %v", err)
+	}
+	_, _, err = api.ResolveItemOrigin("41")
+	if err == nil {
+		t.Error("Expected error for own host item")
+	}
+}
+
+func TestItemsDeleteByFilter(t *testing.T) {
+	var deleted []string
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		switch call.Method {
+		case "item.get":
+			var params map[string]interface{}
+			call.decodeParams(&params, t)
+			if !reflect.DeepEqual(params["output"], []interface{}{"itemid"}) || params["all"] != nil {
+				t.Errorf("Unexpected params: %#v", params)
+			}
+			return []map[string]string{{"itemid": "23"}, {"itemid": "24"}}
+		case "item.delete":
+			call.decodeParams(&deleted, t)
+			return map[string]interface{}{"itemids": deleted}
+		}
+		t.Errorf("Unexpected method %s", call.Method)
+		return nil
+	})
+	defer server.Close()
+
+	count, err := api.ItemsDeleteByFilter(Params{"hostids": "10084", "search": Params{"key_": "test."}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 || !reflect.DeepEqual(deleted, []string{"23", "24"}) {
+		t.Errorf("Unexpected result: %d, %v", count, deleted)
+	}
+
+	deleted = nil
+	for _, params := range []Params{
+		nil, {}, {"output": "extend", "limit": 10}, {"filter": Params{}, "selectHosts": "extend"},
+		{"filter": map[string]string{}}, {"hostids": nil}, {"hostids": []string{}}, {"hostids": ""},
+		{"search": map[string]interface{}{"key_": ""}}, {"filter": map[string][]string{"hostid": nil}},
+		{"webitems": true}, {"inherited": false, "nopermissions": true}, {"templated": true, "limit": 10},
+	} {
+		_, err = api.ItemsDeleteByFilter(params)
+		if err != ErrEmptyFilter {
+			t.Errorf("%#v: expected ErrEmptyFilter, got %v", params, err)
+		}
+	}
+	if deleted != nil {
+		t.Errorf("Unexpected deletion: %v", deleted)
+	}
+
+	count, err = api.ItemsDeleteByFilter(Params{"all": true})
+	if err != nil || count != 2 {
+		t.Errorf("Unexpected result for all items: %d, %v", count, err)
+	}
+}
+
+func TestItemsGetByDiscoveryRule(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		var params map[string]interface{}
+		call.decodeParams(&params, t)
+		switch call.Method {
+		case "discoveryrule.get":
+			if params["itemids"] != "500" {
+				t.Errorf("Unexpected params: %#v", params)
+			}
+			return []map[string]string{{"itemid": "500", "hostid": "10084"}}
+		case "item.get":
+			if params["hostids"] != "10084" || !reflect.DeepEqual(params["filter"], map[string]interface{}{"flags": float64(4)}) || params["selectDiscoveryRule"] == nil {
+				t.Errorf("Unexpected params: %#v", params)
+			}
+			return []interface{}{
+				map[string]interface{}{"itemid": "23", "key_": "vfs.fs.size[/,free]", "flags": "4",
+					"discoveryRule": map[string]string{"itemid": "500", "name": "Mounted filesystems", "key_": "vfs.fs.discovery"}},
+				map[string]interface{}{"itemid": "24", "key_": "net.if.in[eth0]", "flags": "4",
+					"discoveryRule": map[string]string{"itemid": "501", "name": "Network interfaces", "key_": "net.if.discovery"}},
+				map[string]interface{}{"itemid": "25", "key_": "agent.ping", "flags": "0", "discoveryRule": []interface{}{}},
+			}
+		}
+		t.Errorf("Unexpected method %s", call.Method)
+		return nil
+	})
+	defer server.Close()
+
+	items, err := api.ItemsGetByDiscoveryRule("500")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].ItemId != "23" || items[0].DiscoveryRule == nil || items[0].DiscoveryRule.Name != "Mounted filesystems" {
+		t.Errorf("Unexpected items: %#v", items)
+	}
+
+	var plain Item
+	err = json.Unmarshal([]byte(`{"itemid": "25", "flags": "0", "discoveryRule": []}`), &plain)
+	if err != nil || plain.DiscoveryRule != nil {
+		t.Errorf("Unexpected discovery rule of plain item: %#v (%v)", plain.DiscoveryRule, err)
+	}
+}
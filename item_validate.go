@@ -0,0 +1,169 @@
+package zabbix
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Item validation failure.
+type ValidationError struct {
+	Index   int    // index of item in Items, 0 for Item.Validate()
+	Field   string // field name as in JSON, like "key_"
+	Message string // like "is required"
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("Item %d: %s %s.", e.Index, e.Field, e.Message)
+}
+
+// Returned by Item.Validate() and Items.Validate(), contains all failures.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	s := make([]string, len(e))
+	for i, err := range e {
+		s[i] = err.Error()
+	}
+	return strings.Join(s, " ")
+}
+
+func (e ValidationErrors) Unwrap() []error {
+	res := make([]error, len(e))
+	for i, err := range e {
+		res[i] = err
+	}
+	return res
+}
+
+// Checks item fields before creation. Returns ValidationErrors or nil.
+func (item *Item) Validate() error {
+	errs := item.validate(0)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Checks all items and returns ValidationErrors with failures of all of them, or nil.
+// Empty slice is valid.
+func (items Items) Validate() error {
+	var errs ValidationErrors
+	for i := range items {
+		errs = append(errs, items[i].validate(i)...)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func (item *Item) validate(index int) (errs ValidationErrors) {
+	add := func(field, format string, a ...interface{}) {
+		errs = append(errs, &ValidationError{index, field, fmt.Sprintf(format, a...)})
+	}
+
+	if item.HostId == "" {
+		add("hostid", "is required")
+	}
+	if item.Key == "" {
+		add("key_", "is required")
+	}
+	if item.Name == "" {
+		add("name", "is required")
+	}
+	if vt, err := strconv.Atoi(item.ValueType); err != nil || vt < int(Float) || vt > int(Text) {
+		add("value_type", "has unknown value %q", item.ValueType)
+	}
+	if item.LogTimeFmt != "" && item.ValueType != strconv.Itoa(int(Log)) {
+		add("logtimefmt", "is supported only by items with %s value type", Log)
+	}
+	errs = append(errs, item.validateDelay(index)...)
+
+	switch item.Type {
+	case SSHAgent, TELNETAgent:
+		if item.Username == "" {
+			add("username", "is required for %s items", item.Type)
+		}
+		if item.Params == "" {
+			add("params", "is required for %s items", item.Type)
+		}
+		if item.Type == SSHAgent && item.AuthType == PublicKeyAuth {
+			if item.PublicKey == "" {
+				add("publickey", "is required for public key authentication")
+			}
+			if item.PrivateKey == "" {
+				add("privatekey", "is required for public key authentication")
+			}
+		}
+	case HTTPAgent:
+		if item.URL == "" {
+			add("url", "is required for %s items", item.Type)
+		}
+	case DatabaseMonitor:
+		if item.Params == "" {
+			add("params", "is required for %s items", item.Type)
+		}
+	}
+	if item.JMXEndpoint != "" && item.Type != JMXAgent {
+		add("jmx_endpoint", "is supported only by %s items", JMXAgent)
+	}
+	if item.Timeout != "" {
+		if item.Type != HTTPAgent && item.Type != Script {
+			add("timeout", "is supported only by %s and %s items", HTTPAgent, Script)
+		} else if !strings.HasPrefix(item.Timeout, "{$") {
+			if n, err := parseSeconds(item.Timeout); err != nil || n < 1 || n > 60 {
+				add("timeout", "should be from 1 to 60 seconds, got %q", item.Timeout)
+			}
+		}
+	}
+	if item.AuthType == PublicKeyAuth && item.Type != SSHAgent {
+		add("authtype", "public key authentication is supported only by %s items", SSHAgent)
+	}
+	return
+}
+
+// Returns true if items of type are not polled, so they have no update interval.
+func (t ItemType) isPushed() bool {
+	switch t {
+	case ZabbixTrapper, SNMPTrap, DependentItem:
+		return true
+	}
+	return false
+}
+
+// Checks update interval: zero one is allowed for pushed item types like ZabbixTrapper and,
+// as in Zabbix, for polled ones with flexible intervals or for active agent mqtt.get items.
+// DelayRaw, like user macro, is checked by Zabbix only.
+func (item *Item) validateDelay(index int) (errs ValidationErrors) {
+	add := func(format string, a ...interface{}) {
+		errs = append(errs, &ValidationError{index, "delay", fmt.Sprintf(format, a...)})
+	}
+
+	switch {
+	case item.Delay < 0:
+		add("should not be negative")
+	case item.Delay > 0 || item.Type.isPushed() || item.DelayRaw != "":
+	case item.Type == ZabbixAgentActive && strings.HasPrefix(item.Key, "mqtt.get["):
+	default:
+		for _, interval := range item.DelayIntervals {
+			if interval.Type == FlexibleInterval {
+				return
+			}
+		}
+		add("should be positive for %s items without flexible intervals", item.Type)
+	}
+	return
+}
+
+// Checks update intervals of all items, see Item.validateDelay.
+func (items Items) validateDelays() error {
+	var errs ValidationErrors
+	for i := range items {
+		errs = append(errs, items[i].validateDelay(i)...)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
@@ -0,0 +1,322 @@
+package zabbix_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "."
+)
+
+func TestItemsValidate(t *testing.T) {
+	if err := (Items{}).Validate(); err != nil {
+		t.Errorf("Empty items are invalid: %s", err)
+	}
+
+	valid := Item{HostId: "10084", Key: "key.lala.laa", Name: "name for key", Type: ZabbixTrapper, ValueType: "0"}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Valid item is invalid: %s", err)
+	}
+
+	noKey, noName, badValueType := valid, valid, valid
+	noKey.Key = ""
+	noName.Name = ""
+	badValueType.ValueType = "42"
+	err := Items{noKey, valid, noName, badValueType}.Validate()
+
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Expected ValidationErrors, got %#v", err)
+	}
+	expected := map[int]string{0: "key_", 2: "name", 3: "value_type"}
+	if len(errs) != len(expected) {
+		t.Fatalf("Expected %d errors, got %s", len(expected), errs)
+	}
+	for _, e := range errs {
+		if expected[e.Index] != e.Field {
+			t.Errorf("Unexpected error %s", e)
+		}
+	}
+
+	var e *ValidationError
+	if !errors.As(err, &e) || e.Index != 0 {
+		t.Errorf("Failed to unwrap first error: %#v", e)
+	}
+}
+
+func TestItemsCreateSSH(t *testing.T) {
+	var created []map[string]interface{}
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		if call.Method == "item.get" {
+			return []map[string]string{{"itemid": "23", "key_": "ssh.run[uptime]", "password": "secret-password", "privatekey": "secret-key"}}
+		}
+		call.decodeParams(&created, t)
+		return map[string]interface{}{"itemids": []string{"23", "24"}}
+	})
+	defer server.Close()
+	var buf bytes.Buffer
+	api.Logger = log.New(&buf, "", 0)
+
+	items := Items{
+		{
+			HostId: "10084", InterfaceId: "30", Key: "ssh.run[uptime]", Name: "Uptime", Type: SSHAgent, ValueType: "4", Delay: 60,
+			AuthType: PasswordAuth, Username: "zabbix", Password: "secret-password", Params: "uptime",
+		},
+		{
+			HostId: "10084", InterfaceId: "30", Key: "ssh.run[df]", Name: "Disk", Type: SSHAgent, ValueType: "4", Delay: 60,
+			AuthType: PublicKeyAuth, Username: "zabbix", PublicKey: "id_rsa.pub", PrivateKey: "id_rsa", Password: "secret-passphrase", Params: "df -h",
+		},
+	}
+	if err := items.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	err := api.ItemsCreate(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(created) != 2 || created[0]["password"] != "secret-password" || created[1]["authtype"] != float64(1) || created[1]["privatekey"] != "id_rsa" {
+		t.Errorf("Unexpected payload: %#v", created)
+	}
+	if strings.Contains(buf.String(), "secret-") || !strings.Contains(buf.String(), "ssh.run[df]") {
+		t.Errorf("Unexpected log:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	got, err := api.ItemsGet(Params{"itemids": "23"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0].Password != "secret-password" {
+		t.Errorf("Unexpected item: %#v", got[0])
+	}
+	if strings.Contains(buf.String(), "secret-") || !strings.Contains(buf.String(), "ssh.run[uptime]") {
+		t.Errorf("Unexpected log:\n%s", buf.String())
+	}
+}
+
+func TestItemValidateSSH(t *testing.T) {
+	item := Item{HostId: "10084", Key: "ssh.run[df]", Name: "Disk", Type: SSHAgent, ValueType: "4", Delay: 60, AuthType: PublicKeyAuth, Username: "zabbix", Params: "df -h"}
+	var errs ValidationErrors
+	if !errors.As(item.Validate(), &errs) || len(errs) != 2 || errs[0].Field != "publickey" || errs[1].Field != "privatekey" {
+		t.Errorf("Unexpected errors: %v", errs)
+	}
+}
+
+func TestItemsCreateLog(t *testing.T) {
+	var created []map[string]interface{}
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		call.decodeParams(&created, t)
+		return map[string]interface{}{"itemids": []string{"23"}}
+	})
+	defer server.Close()
+
+	items := Items{{
+		HostId: "10084", Key: "log[/var/log/app.log]", Name: "App log", Type: ZabbixAgentActive, ValueType: "2", Delay: 30,
+		LogTimeFmt: "yyyyMMdd:hhmmss",
+	}}
+	if err := items.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	err := api.ItemsCreate(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 1 || created[0]["logtimefmt"] != "yyyyMMdd:hhmmss" {
+		t.Errorf("Unexpected payload: %#v", created)
+	}
+
+	for _, vt := range []string{"1", "4"} {
+		item := items[0]
+		item.ValueType = vt
+		errs, _ := item.Validate().(ValidationErrors)
+		if len(errs) != 1 || errs[0].Field != "logtimefmt" {
+			t.Errorf("Value type %s: unexpected errors %v", vt, errs)
+		}
+	}
+}
+
+func TestItemsCreateTimeout(t *testing.T) {
+	var created []map[string]interface{}
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		call.decodeParams(&created, t)
+		return map[string]interface{}{"itemids": []string{"23"}}
+	})
+	defer server.Close()
+
+	items := Items{{
+		HostId: "10084", Key: "api.status", Name: "API status", Type: HTTPAgent, ValueType: "4", Delay: 60,
+		URL: "https://example.com/status", Timeout: "10s",
+	}}
+	if err := items.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	err := api.ItemsCreate(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 1 || created[0]["timeout"] != "10s" || created[0]["type"] != float64(19) {
+		t.Errorf("Unexpected payload: %#v", created)
+	}
+
+	for _, c := range []struct {
+		itemType ItemType
+		timeout  string
+		valid    bool
+	}{
+		{Script, "1m", true},
+		{Script, "{$SCRIPT.TIMEOUT}", true},
+		{HTTPAgent, "0", false},
+		{HTTPAgent, "2m", false},
+		{HTTPAgent, "soon", false},
+		{ZabbixAgent, "3s", false},
+	} {
+		item := items[0]
+		item.Type, item.Timeout = c.itemType, c.timeout
+		err := item.Validate()
+		if c.valid != (err == nil) {
+			t.Errorf("%s %q: unexpected result %v", c.itemType, c.timeout, err)
+		}
+	}
+}
+
+func TestItemsCreateHTTPAgent(t *testing.T) {
+	var created []map[string]interface{}
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		call.decodeParams(&created, t)
+		return map[string]interface{}{"itemids": []string{"23"}}
+	})
+	defer server.Close()
+
+	items := Items{{
+		HostId: "10084", Key: "api.health", Name: "API health", Type: HTTPAgent, ValueType: "4", Delay: 60,
+		URL: "https://example.com/health", RequestMethod: HTTPGet,
+		Headers:     HTTPHeaders{"Accept": "application/json", "X-Token": "{$API.TOKEN}"},
+		StatusCodes: "200,204",
+	}}
+	if err := items.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	err := api.ItemsCreate(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedHeaders := map[string]interface{}{"Accept": "application/json", "X-Token": "{$API.TOKEN}"}
+	if len(created) != 1 || created[0]["url"] != "https://example.com/health" || created[0]["status_codes"] != "200,204" ||
+		!reflect.DeepEqual(created[0]["headers"], expectedHeaders) {
+		t.Errorf("Unexpected payload: %#v", created)
+	}
+	if _, present := created[0]["follow_redirects"]; present {
+		t.Errorf("Unexpected follow_redirects: %#v", created[0])
+	}
+
+	noRedirects := 0
+	items[0].FollowRedirects = &noRedirects
+	err = api.ItemsCreate(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created[0]["follow_redirects"] != "0" {
+		t.Errorf("Unexpected follow_redirects: %#v", created[0])
+	}
+
+	item := items[0]
+	item.URL = ""
+	errs, _ := item.Validate().(ValidationErrors)
+	if len(errs) != 1 || errs[0].Field != "url" {
+		t.Errorf("Unexpected errors: %v", errs)
+	}
+
+	var decoded Item
+	if err = json.Unmarshal([]byte(`{"follow_redirects": "0"}`), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.FollowRedirects == nil || *decoded.FollowRedirects != 0 {
+		t.Errorf("Unexpected follow_redirects: %#v", decoded.FollowRedirects)
+	}
+
+	for data, expected := range map[string]HTTPHeaders{
+		`{"headers": []}`:                       nil,
+		`{"headers": {"Accept": "text/plain"}}`: {"Accept": "text/plain"},
+	} {
+		var item Item
+		if err = json.Unmarshal([]byte(data), &item); err != nil {
+			t.Fatalf("%s: %s", data, err)
+		}
+		if !reflect.DeepEqual(item.Headers, expected) {
+			t.Errorf("%s: unexpected headers %#v", data, item.Headers)
+		}
+	}
+}
+
+func TestItemsCreateJMX(t *testing.T) {
+	var created []map[string]interface{}
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		call.decodeParams(&created, t)
+		return map[string]interface{}{"itemids": []string{"23", "24"}}
+	})
+	defer server.Close()
+
+	items := Items{
+		{HostId: "10084", InterfaceId: "31", Key: `jmx["java.lang:type=Memory","HeapMemoryUsage.used"]`, Name: "Heap used", Type: JMXAgent, ValueType: "3", Delay: 60},
+		{HostId: "10084", InterfaceId: "31", Key: `jmx["java.lang:type=Threading","ThreadCount"]`, Name: "Threads", Type: JMXAgent, ValueType: "3", Delay: 60,
+			JMXEndpoint: "service:jmx:rmi:///jndi/rmi://{HOST.CONN}:9999/jmxrmi"},
+	}
+	if err := items.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	err := api.ItemsCreate(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 2 || created[0]["jmx_endpoint"] != DefaultJMXEndpoint || created[1]["jmx_endpoint"] != items[1].JMXEndpoint {
+		t.Errorf("Unexpected payload: %#v", created)
+	}
+
+	item := items[0]
+	item.Type = ZabbixAgent
+	errs, _ := item.Validate().(ValidationErrors)
+	if len(errs) != 1 || errs[0].Field != "jmx_endpoint" {
+		t.Errorf("Unexpected errors: %v", errs)
+	}
+}
+
+func TestItemsCreateDelay(t *testing.T) {
+	var created []map[string]interface{}
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		call.decodeParams(&created, t)
+		return map[string]interface{}{"itemids": []string{"23", "24", "25"}}
+	})
+	defer server.Close()
+
+	items := Items{
+		{HostId: "10084", Key: "trap.value", Name: "Trap", Type: ZabbixTrapper, ValueType: "3"},
+		{HostId: "10084", Key: "agent.ping", Name: "Ping", Type: ZabbixAgentActive, ValueType: "3",
+			DelayIntervals: []DelayInterval{{Type: FlexibleInterval, Interval: "60s", Period: "1-5,09:00-18:00"}}},
+		{HostId: "10084", Key: "mqtt.get[tcp://broker,sensors/temp]", Name: "Temperature", Type: ZabbixAgentActive, ValueType: "0"},
+	}
+	if err := items.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	err := api.ItemsCreate(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 3 || created[0]["delay"] != float64(0) {
+		t.Errorf("Unexpected payload: %#v", created)
+	}
+
+	created = nil
+	err = api.ItemsCreate(Items{{HostId: "10084", Key: "agent.version", Name: "Version", Type: ZabbixAgent, ValueType: "1"}})
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Field != "delay" {
+		t.Errorf("Expected delay error, got %v", err)
+	}
+	if created != nil {
+		t.Errorf("Unexpected payload: %#v", created)
+	}
+}
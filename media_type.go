@@ -0,0 +1,123 @@
+package zabbix
+
+import (
+	"fmt"
+)
+
+type (
+	MediaTypeType int
+)
+
+const (
+	EmailMediaType   MediaTypeType = 0
+	ScriptMediaType  MediaTypeType = 1
+	SMSMediaType     MediaTypeType = 2
+	WebhookMediaType MediaTypeType = 4
+)
+
+// https://www.zabbix.com/documentation/4.4/manual/api/reference/mediatype/object#webhook_parameters
+type MediaTypeParameter struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type MediaTypeParameters []MediaTypeParameter
+
+// https://www.zabbix.com/documentation/4.4/manual/api/reference/mediatype/object
+type MediaType struct {
+	MediaTypeId string        `json:"mediatypeid,omitempty"`
+	Name        string        `json:"name"`
+	Type        MediaTypeType `json:"type,string"`
+	Status      Status        `json:"status,string"`
+	ExecPath    string        `json:"exec_path,omitempty"`
+
+	// Fields below used only by webhooks (Zabbix 4.4+)
+	Script     string              `json:"script,omitempty"`
+	Parameters MediaTypeParameters `json:"parameters,omitempty"`
+}
+
+type MediaTypes []MediaType
+
+// Checks that webhooks have script and parameter names are unique.
+func (mediaTypes MediaTypes) validate() error {
+	for _, mediaType := range mediaTypes {
+		if mediaType.Type == WebhookMediaType && mediaType.Script == "" {
+			return fmt.Errorf("Webhook media type %s should have script.", mediaType.Name)
+		}
+
+		names := make(map[string]bool, len(mediaType.Parameters))
+		for _, p := range mediaType.Parameters {
+			if names[p.Name] {
+				return fmt.Errorf("Media type %s has duplicate parameter %s.", mediaType.Name, p.Name)
+			}
+			names[p.Name] = true
+		}
+	}
+	return nil
+}
+
+// Wrapper for mediatype.get: https://www.zabbix.com/documentation/4.4/manual/api/reference/mediatype/get
+func (api *API) MediaTypesGet(params Params) (res MediaTypes, err error) {
+	params = params.Clone()
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	err = api.callInto("mediatype.get", params, &res)
+	return
+}
+
+// Wrapper for mediatype.create: https://www.zabbix.com/documentation/4.4/manual/api/reference/mediatype/create
+func (api *API) MediaTypesCreate(mediaTypes MediaTypes) (err error) {
+	err = mediaTypes.validate()
+	if err != nil {
+		return
+	}
+
+	response, err := api.CallWithError("mediatype.create", mediaTypes)
+	if err != nil {
+		return
+	}
+
+	mediatypeids, err := response.ResultIDs("mediatypeids")
+	if err != nil {
+		return
+	}
+	for i, id := range mediatypeids {
+		mediaTypes[i].MediaTypeId = id
+	}
+	return
+}
+
+// Wrapper for mediatype.delete: https://www.zabbix.com/documentation/4.4/manual/api/reference/mediatype/delete
+// Cleans MediaTypeId in all mediaTypes elements if call succeed.
+func (api *API) MediaTypesDelete(mediaTypes MediaTypes) (err error) {
+	ids := make([]string, len(mediaTypes))
+	for i, mediaType := range mediaTypes {
+		ids[i] = mediaType.MediaTypeId
+	}
+
+	err = api.MediaTypesDeleteByIds(ids)
+	if err == nil {
+		for i := range mediaTypes {
+			mediaTypes[i].MediaTypeId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for mediatype.delete: https://www.zabbix.com/documentation/4.4/manual/api/reference/mediatype/delete
+func (api *API) MediaTypesDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("mediatype.delete", ids)
+	if err != nil {
+		return
+	}
+
+	mediatypeids, err := response.ResultIDs("mediatypeids")
+	if err != nil {
+		return
+	}
+	if len(ids) != len(mediatypeids) {
+		err = &ExpectedMore{len(ids), len(mediatypeids)}
+	}
+	return
+}
@@ -0,0 +1,70 @@
+package zabbix_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "."
+)
+
+func TestMediaTypesCreateWebhook(t *testing.T) {
+	var created []map[string]interface{}
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		if call.Method != "mediatype.create" {
+			t.Errorf("Unexpected method %s", call.Method)
+		}
+		call.decodeParams(&created, t)
+		return map[string]interface{}{"mediatypeids": []string{"42"}}
+	})
+	defer server.Close()
+
+	mediaTypes := MediaTypes{{
+		Name:   "Chat",
+		Type:   WebhookMediaType,
+		Script: "return 'OK';",
+		Parameters: MediaTypeParameters{
+			{Name: "URL", Value: "https://chat.example.com/hook"},
+			{Name: "Message", Value: "{ALERT.MESSAGE}"},
+		},
+	}}
+	err := api.MediaTypesCreate(mediaTypes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mediaTypes[0].MediaTypeId != "42" {
+		t.Errorf("Id is not set: %#v", mediaTypes[0])
+	}
+
+	expected := []map[string]interface{}{{
+		"name":   "Chat",
+		"type":   "4",
+		"status": "0",
+		"script": "return 'OK';",
+		"parameters": []interface{}{
+			map[string]interface{}{"name": "URL", "value": "https://chat.example.com/hook"},
+			map[string]interface{}{"name": "Message", "value": "{ALERT.MESSAGE}"},
+		},
+	}}
+	if !reflect.DeepEqual(created, expected) {
+		t.Errorf("Unexpected payload:\n%#v\n%#v", created, expected)
+	}
+}
+
+func TestMediaTypesValidation(t *testing.T) {
+	api := getAPI(t)
+
+	err := api.MediaTypesCreate(MediaTypes{{Name: "No script", Type: WebhookMediaType}})
+	if err == nil {
+		t.Error("Expected error for webhook without script")
+	}
+
+	err = api.MediaTypesCreate(MediaTypes{{
+		Name:       "Duplicates",
+		Type:       WebhookMediaType,
+		Script:     "return 'OK';",
+		Parameters: MediaTypeParameters{{Name: "URL", Value: "a"}, {Name: "URL", Value: "b"}},
+	}})
+	if err == nil {
+		t.Error("Expected error for duplicate parameters")
+	}
+}
@@ -0,0 +1,84 @@
+package zabbix
+
+import (
+	"reflect"
+)
+
+// Returns deep copy of params: nested maps and slices are copied too.
+func (p Params) Clone() Params {
+	if p == nil {
+		return Params{}
+	}
+	res := make(Params, len(p))
+	for k, v := range p {
+		res[k] = deepCopy(v)
+	}
+	return res
+}
+
+// Returns deep copy of params with other merged in. Values from other take precedence,
+// nested maps present in both are merged recursively. Neither p nor other are changed.
+func (p Params) Merge(other Params) Params {
+	res := p.Clone()
+	for k, v := range other {
+		dst, ok1 := asMap(res[k])
+		src, ok2 := asMap(v)
+		if ok1 && ok2 {
+			res[k] = map[string]interface{}(Params(dst).Merge(Params(src)))
+			continue
+		}
+		res[k] = deepCopy(v)
+	}
+	return res
+}
+
+// Converts Params and map[string]interface{} to the latter.
+func asMap(v interface{}) (map[string]interface{}, bool) {
+	switch v := v.(type) {
+	case Params:
+		return v, true
+	case map[string]interface{}:
+		return v, true
+	}
+	return nil, false
+}
+
+// Returns deep copy of maps and slices, other values are returned as is.
+func deepCopy(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.IsNil() {
+			return v
+		}
+		res := reflect.MakeMapWithSize(rv.Type(), rv.Len())
+		for _, key := range rv.MapKeys() {
+			res.SetMapIndex(key, copyValue(rv.MapIndex(key), rv.Type().Elem()))
+		}
+		return res.Interface()
+
+	case reflect.Slice:
+		if rv.IsNil() {
+			return v
+		}
+		res := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			res.Index(i).Set(copyValue(rv.Index(i), rv.Type().Elem()))
+		}
+		return res.Interface()
+	}
+	return v
+}
+
+// Deep copies v to value of type t.
+func copyValue(v reflect.Value, t reflect.Type) reflect.Value {
+	c := deepCopy(v.Interface())
+	if c == nil {
+		return reflect.Zero(t)
+	}
+	return reflect.ValueOf(c).Convert(t)
+}
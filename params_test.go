@@ -0,0 +1,64 @@
+package zabbix_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "."
+)
+
+func TestParamsClone(t *testing.T) {
+	p := Params{
+		"hostids": []string{"1", "2"},
+		"filter":  map[string]interface{}{"key_": []interface{}{"a"}},
+		"search":  Params{"name": "cpu"},
+		"limit":   10,
+	}
+	c := p.Clone()
+	if !reflect.DeepEqual(p, c) {
+		t.Fatalf("Clone is not equal:\n%#v\n%#v", p, c)
+	}
+
+	c["hostids"].([]string)[0] = "3"
+	c["filter"].(map[string]interface{})["key_"].([]interface{})[0] = "b"
+	c["search"].(Params)["name"] = "memory"
+	c["limit"] = 20
+	expected := Params{
+		"hostids": []string{"1", "2"},
+		"filter":  map[string]interface{}{"key_": []interface{}{"a"}},
+		"search":  Params{"name": "cpu"},
+		"limit":   10,
+	}
+	if !reflect.DeepEqual(p, expected) {
+		t.Errorf("Original is changed: %#v", p)
+	}
+}
+
+func TestParamsMerge(t *testing.T) {
+	defaults := Params{"output": "extend", "filter": map[string]interface{}{"status": 0, "flags": 0}}
+	p := defaults.Merge(Params{"hostids": "1", "filter": map[string]interface{}{"flags": 4}})
+
+	expected := Params{"output": "extend", "hostids": "1", "filter": map[string]interface{}{"status": 0, "flags": 4}}
+	if !reflect.DeepEqual(p, expected) {
+		t.Errorf("Unexpected merge result: %#v", p)
+	}
+	if defaults["filter"].(map[string]interface{})["flags"] != 0 {
+		t.Errorf("Defaults are changed: %#v", defaults)
+	}
+}
+
+func TestItemsGetDoesNotChangeParams(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		return []interface{}{}
+	})
+	defer server.Close()
+
+	params := Params{"hostids": []string{"1"}}
+	_, err := api.ItemsGet(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(params, Params{"hostids": []string{"1"}}) {
+		t.Errorf("Params are changed: %#v", params)
+	}
+}
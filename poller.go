@@ -0,0 +1,99 @@
+package zabbix
+
+import (
+	"context"
+	"time"
+)
+
+// Change of item value or polling error sent by Poller.
+type PollUpdate struct {
+	ItemId string
+	Value  string
+	Err    error // if set, ItemId and Value are empty
+}
+
+// Polls last values of items and sends changes to C.
+type Poller struct {
+	C <-chan PollUpdate
+
+	api      *API
+	ids      []string
+	interval time.Duration
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// Starts polling itemIds every interval. Stop() should be called to release resources.
+func (api *API) NewPoller(itemIds []string, interval time.Duration) *Poller {
+	return api.NewPollerContext(context.Background(), itemIds, interval)
+}
+
+// Like NewPoller, but also stops polling when ctx is done.
+// Values received by the first poll are remembered, so only later changes are sent.
+// Updates are not buffered: polling waits for C to be read. C is closed when polling stops.
+func (api *API) NewPollerContext(ctx context.Context, itemIds []string, interval time.Duration) *Poller {
+	ctx, cancel := context.WithCancel(ctx)
+	c := make(chan PollUpdate)
+	p := &Poller{
+		C:        c,
+		api:      api,
+		ids:      itemIds,
+		interval: interval,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	go p.run(ctx, c)
+	return p
+}
+
+// Stops polling and waits for it to finish.
+func (p *Poller) Stop() {
+	p.cancel()
+	<-p.done
+}
+
+func (p *Poller) run(ctx context.Context, c chan<- PollUpdate) {
+	defer close(p.done)
+	defer close(c)
+
+	send := func(u PollUpdate) bool {
+		select {
+		case c <- u:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	var last map[string]string
+	for {
+		values, err := p.api.ItemsGetLastValues(p.ids)
+		switch {
+		case err != nil:
+			if !send(PollUpdate{Err: err}) {
+				return
+			}
+		case last == nil:
+			last = values
+		default:
+			for _, id := range p.ids {
+				value, ok := values[id]
+				if prev, seen := last[id]; ok && (!seen || prev != value) {
+					last[id] = value
+					if !send(PollUpdate{ItemId: id, Value: value}) {
+						return
+					}
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
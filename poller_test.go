@@ -0,0 +1,54 @@
+package zabbix_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPoller(t *testing.T) {
+	var m sync.Mutex
+	var polls int
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		m.Lock()
+		defer m.Unlock()
+		polls++
+		value := "1"
+		if polls > 1 {
+			value = "2"
+		}
+		return []map[string]string{
+			{"itemid": "23", "lastclock": "1400000000", "lastvalue": value},
+			{"itemid": "24", "lastclock": "1400000000", "lastvalue": "0"},
+		}
+	})
+	defer server.Close()
+
+	p := api.NewPoller([]string{"23", "24"}, 5*time.Millisecond)
+	select {
+	case u := <-p.C:
+		if u.Err != nil || u.ItemId != "23" || u.Value != "2" {
+			t.Errorf("Unexpected update: %#v", u)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("No update")
+	}
+
+	// let poller run a few more times without changes
+	time.Sleep(30 * time.Millisecond)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for u := range p.C {
+			t.Errorf("Unexpected update: %#v", u)
+		}
+	}()
+	p.Stop()
+	wg.Wait() // C is closed
+	m.Lock()
+	if polls < 3 {
+		t.Errorf("Expected more polls, got %d", polls)
+	}
+	m.Unlock()
+}
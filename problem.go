@@ -0,0 +1,58 @@
+package zabbix
+
+// Maintenance suppressing problem, returned by selectSuppressionData query parameter (Zabbix 4.0+).
+type SuppressionData struct {
+	MaintenanceId string `json:"maintenanceid"`
+	SuppressUntil string `json:"suppress_until"` // "0" if maintenance has no end
+}
+
+// https://www.zabbix.com/documentation/4.0/manual/api/reference/problem/object
+type Problem struct {
+	EventId      string       `json:"eventid"`
+	Source       EventSource  `json:"source,string"`
+	Object       EventObject  `json:"object,string"`
+	ObjectId     string       `json:"objectid"`
+	Clock        string       `json:"clock"`
+	REventId     string       `json:"r_eventid"`
+	Name         string       `json:"name"`
+	Severity     PriorityType `json:"severity,string"`
+	Acknowledged int          `json:"acknowledged,string"`
+
+	// Set if problem is in maintenance (Zabbix 4.0+).
+	Suppressed      int               `json:"suppressed,string"`
+	SuppressionData []SuppressionData `json:"suppression_data,omitempty"`
+
+	// Returned by selectTags query parameter.
+	Tags Tags `json:"tags,omitempty"`
+}
+
+type Problems []Problem
+
+// Returns true if problem is suppressed by maintenance.
+func (p *Problem) IsSuppressed() bool {
+	return p.Suppressed != 0
+}
+
+// Wrapper for problem.get: https://www.zabbix.com/documentation/4.0/manual/api/reference/problem/get
+func (api *API) ProblemsGet(params Params) (res Problems, err error) {
+	params = params.Clone()
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	err = api.callInto("problem.get", params, &res)
+	return
+}
+
+// Gets unresolved trigger problems with their suppression data.
+// Problems suppressed by maintenance are filtered out by Zabbix unless includeSuppressed is set.
+func (api *API) ProblemsGetActive(includeSuppressed bool) (res Problems, err error) {
+	params := Params{
+		"source":                TriggerEventSource,
+		"object":                TriggerEventObject,
+		"selectSuppressionData": "extend",
+	}
+	if !includeSuppressed {
+		params["suppressed"] = false
+	}
+	return api.ProblemsGet(params)
+}
@@ -0,0 +1,51 @@
+package zabbix_test
+
+import (
+	"testing"
+
+	. "."
+)
+
+func TestProblemsGetActive(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		var params map[string]interface{}
+		call.decodeParams(&params, t)
+		if call.Method != "problem.get" || params["selectSuppressionData"] != "extend" {
+			t.Errorf("Unexpected call %s: %#v", call.Method, params)
+		}
+		res := []map[string]interface{}{
+			{"eventid": "1", "source": "0", "object": "0", "objectid": "13", "severity": "4", "suppressed": "0", "suppression_data": []interface{}{}},
+		}
+		if suppressed, present := params["suppressed"]; present {
+			if suppressed != false {
+				t.Errorf("Unexpected suppressed %#v", suppressed)
+			}
+			return res
+		}
+		return append(res, map[string]interface{}{
+			"eventid": "2", "source": "0", "object": "0", "objectid": "14", "severity": "2", "suppressed": "1",
+			"suppression_data": []map[string]string{{"maintenanceid": "3", "suppress_until": "1400003600"}},
+		})
+	})
+	defer server.Close()
+
+	problems, err := api.ProblemsGetActive(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 1 || problems[0].IsSuppressed() || problems[0].Severity != High {
+		t.Errorf("Unexpected problems: %#v", problems)
+	}
+
+	problems, err = api.ProblemsGetActive(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 2 || !problems[1].IsSuppressed() {
+		t.Fatalf("Unexpected problems: %#v", problems)
+	}
+	data := problems[1].SuppressionData
+	if len(data) != 1 || data[0].MaintenanceId != "3" || data[0].SuppressUntil != "1400003600" {
+		t.Errorf("Unexpected suppression data: %#v", data)
+	}
+}
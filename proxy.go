@@ -0,0 +1,196 @@
+package zabbix
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type (
+	ProxyStatus int
+)
+
+const (
+	ActiveProxy  ProxyStatus = 5
+	PassiveProxy ProxyStatus = 6
+)
+
+// https://www.zabbix.com/documentation/2.2/manual/api/reference/proxy/object#proxy_interface
+type ProxyInterface struct {
+	InterfaceId string `json:"interfaceid,omitempty"`
+	DNS         string `json:"dns"`
+	IP          string `json:"ip"`
+	Port        string `json:"port"`
+	UseIP       int    `json:"useip,string"`
+}
+
+// https://www.zabbix.com/documentation/2.2/manual/api/reference/proxy/object
+type Proxy struct {
+	ProxyId string      `json:"proxyid,omitempty"`
+	Host    string      `json:"host"`
+	Status  ProxyStatus `json:"status,string,omitempty"`
+
+	// Interface is required for passive proxies and forbidden for active ones.
+	// Returned by selectInterface query parameter.
+	Interface *ProxyInterface `json:"interface,omitempty"`
+
+	// Read-only time of last contact with proxy, zero if proxy was never seen. Not sent on create or update.
+	LastAccess time.Time `json:"-"`
+}
+
+// Decodes lastaccess into LastAccess and interface into Interface.
+func (proxy *Proxy) UnmarshalJSON(b []byte) (err error) {
+	type plain Proxy
+	aux := struct {
+		*plain
+		LastAccess string          `json:"lastaccess"`
+		Interface  json.RawMessage `json:"interface"`
+	}{plain: (*plain)(proxy)}
+	err = json.Unmarshal(b, &aux)
+	if err != nil {
+		return
+	}
+
+	// active proxies have empty array
+	if len(aux.Interface) != 0 && !isEmptyArray(aux.Interface) && string(aux.Interface) != "null" {
+		proxy.Interface = new(ProxyInterface)
+		if err = json.Unmarshal(aux.Interface, proxy.Interface); err != nil {
+			return
+		}
+	}
+	proxy.LastAccess, err = parseUnixTime(aux.LastAccess)
+	return
+}
+
+type Proxies []Proxy
+
+// Returns proxies last seen more than olderThan before now, including never seen ones.
+func (proxies Proxies) Stale(now time.Time, olderThan time.Duration) (res Proxies) {
+	deadline := now.Add(-olderThan)
+	for _, proxy := range proxies {
+		if proxy.LastAccess.IsZero() || proxy.LastAccess.Before(deadline) {
+			res = append(res, proxy)
+		}
+	}
+	return
+}
+
+// Checks that only passive proxies have interface.
+// Proxies without status (for example, in partial updates) are not checked.
+func (proxies Proxies) validate() error {
+	for _, proxy := range proxies {
+		switch {
+		case proxy.Status == PassiveProxy && proxy.Interface == nil:
+			return fmt.Errorf("Passive proxy %s should have interface.", proxy.Host)
+		case proxy.Status == ActiveProxy && proxy.Interface != nil:
+			return fmt.Errorf("Active proxy %s should not have interface.", proxy.Host)
+		}
+	}
+	return nil
+}
+
+// Wrapper for proxy.get: https://www.zabbix.com/documentation/2.2/manual/api/reference/proxy/get
+func (api *API) ProxiesGet(params Params) (res Proxies, err error) {
+	params = params.Clone()
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	if _, present := params["selectInterface"]; !present {
+		params["selectInterface"] = "extend"
+	}
+	err = api.callInto("proxy.get", params, &res)
+	return
+}
+
+// Gets proxy by Id only if there is exactly 1 matching proxy.
+func (api *API) ProxyGetById(id string) (res *Proxy, err error) {
+	proxies, err := api.ProxiesGet(Params{"proxyids": id})
+	if err != nil {
+		return
+	}
+
+	if len(proxies) == 1 {
+		res = &proxies[0]
+	} else {
+		e := ExpectedOneResult(len(proxies))
+		err = &e
+	}
+	return
+}
+
+// Gets proxies which were not seen by server for more than olderThan, including never seen ones.
+func (api *API) ProxiesGetStale(olderThan time.Duration) (res Proxies, err error) {
+	proxies, err := api.ProxiesGet(Params{})
+	if err != nil {
+		return
+	}
+	res = proxies.Stale(time.Now(), olderThan)
+	return
+}
+
+// Wrapper for proxy.create: https://www.zabbix.com/documentation/2.2/manual/api/reference/proxy/create
+func (api *API) ProxiesCreate(proxies Proxies) (err error) {
+	err = proxies.validate()
+	if err != nil {
+		return
+	}
+
+	response, err := api.CallWithError("proxy.create", proxies)
+	if err != nil {
+		return
+	}
+
+	proxyids, err := response.ResultIDs("proxyids")
+	if err != nil {
+		return
+	}
+	for i, id := range proxyids {
+		proxies[i].ProxyId = id
+	}
+	return
+}
+
+// Wrapper for proxy.update: https://www.zabbix.com/documentation/2.2/manual/api/reference/proxy/update
+func (api *API) ProxiesUpdate(proxies Proxies) (err error) {
+	err = proxies.validate()
+	if err != nil {
+		return
+	}
+
+	_, err = api.CallWithError("proxy.update", proxies)
+	return
+}
+
+// Wrapper for proxy.delete: https://www.zabbix.com/documentation/2.2/manual/api/reference/proxy/delete
+// Cleans ProxyId in all proxies elements if call succeed.
+func (api *API) ProxiesDelete(proxies Proxies) (err error) {
+	ids := make([]string, len(proxies))
+	for i, proxy := range proxies {
+		ids[i] = proxy.ProxyId
+	}
+
+	err = api.ProxiesDeleteByIds(ids)
+	if err == nil {
+		for i := range proxies {
+			proxies[i].ProxyId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for proxy.delete: https://www.zabbix.com/documentation/2.2/manual/api/reference/proxy/delete
+func (api *API) ProxiesDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("proxy.delete", ids)
+	if err != nil {
+		return
+	}
+
+	proxyids, err := response.ResultIDs("proxyids")
+	if err != nil {
+		return
+	}
+	if len(ids) != len(proxyids) {
+		err = &ExpectedMore{len(ids), len(proxyids)}
+	}
+	return
+}
@@ -0,0 +1,108 @@
+package zabbix_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	. "."
+)
+
+func CreateProxy(status ProxyStatus, t *testing.T) *Proxy {
+	proxies := Proxies{{Host: fmt.Sprintf("proxy-%s-%d", getHost(), rand.Int()), Status: status}}
+	if status == PassiveProxy {
+		proxies[0].Interface = &ProxyInterface{IP: "127.0.0.1", Port: "10051", UseIP: 1}
+	}
+	err := getAPI(t).ProxiesCreate(proxies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &proxies[0]
+}
+
+func DeleteProxy(proxy *Proxy, t *testing.T) {
+	err := getAPI(t).ProxiesDelete(Proxies{*proxy})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProxies(t *testing.T) {
+	api := getAPI(t)
+
+	active := CreateProxy(ActiveProxy, t)
+	defer DeleteProxy(active, t)
+
+	passive := CreateProxy(PassiveProxy, t)
+	defer DeleteProxy(passive, t)
+
+	active2, err := api.ProxyGetById(active.ProxyId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if active2.Host != active.Host || active2.Status != ActiveProxy {
+		t.Errorf("Bad active proxy: %#v", active2)
+	}
+
+	passive2, err := api.ProxyGetById(passive.ProxyId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if passive2.Status != PassiveProxy || passive2.Interface == nil || passive2.Interface.Port != "10051" {
+		t.Errorf("Bad passive proxy: %#v", passive2)
+	}
+
+	passive.Interface.Port = "10052"
+	err = api.ProxiesUpdate(Proxies{*passive})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProxiesValidation(t *testing.T) {
+	api := getAPI(t)
+	iface := &ProxyInterface{IP: "127.0.0.1", Port: "10051", UseIP: 1}
+
+	err := api.ProxiesCreate(Proxies{{Host: "active", Status: ActiveProxy, Interface: iface}})
+	if err == nil {
+		t.Error("Expected error for active proxy with interface")
+	}
+
+	err = api.ProxiesCreate(Proxies{{Host: "passive", Status: PassiveProxy}})
+	if err == nil {
+		t.Error("Expected error for passive proxy without interface")
+	}
+}
+
+func TestProxiesStale(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		if call.Method != "proxy.get" {
+			t.Errorf("Unexpected method %s", call.Method)
+			return nil
+		}
+		return []map[string]interface{}{
+			{"proxyid": "1", "host": "fresh", "status": "5", "lastaccess": "1400000540", "interface": []interface{}{}},
+			{"proxyid": "2", "host": "dead", "status": "6", "lastaccess": "1399990000",
+				"interface": map[string]string{"interfaceid": "7", "dns": "", "ip": "192.0.2.1", "port": "10051", "useip": "1"}},
+			{"proxyid": "3", "host": "new", "status": "5", "lastaccess": "0", "interface": []interface{}{}},
+		}
+	})
+	defer server.Close()
+
+	proxies, err := api.ProxiesGet(Params{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proxies[0].LastAccess.Equal(time.Unix(1400000540, 0)) || !proxies[2].LastAccess.IsZero() {
+		t.Errorf("Unexpected lastaccess: %v, %v", proxies[0].LastAccess, proxies[2].LastAccess)
+	}
+	if proxies[0].Interface != nil || proxies[1].Interface == nil || proxies[1].Interface.IP != "192.0.2.1" {
+		t.Errorf("Unexpected interfaces: %#v", proxies)
+	}
+
+	stale := proxies.Stale(time.Unix(1400000600, 0), 5*time.Minute)
+	if len(stale) != 2 || stale[0].Host != "dead" || stale[1].Host != "new" {
+		t.Errorf("Unexpected stale proxies: %#v", stale)
+	}
+}
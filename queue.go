@@ -0,0 +1,112 @@
+package zabbix
+
+import (
+	"sort"
+	"strconv"
+	"time"
+)
+
+type (
+	QueueMode int
+)
+
+const (
+	QueueOverview QueueMode = 0 // only counts of overdue items
+	QueueDetails  QueueMode = 1 // counts and overdue items themselves
+)
+
+// Lower bounds of overdue buckets, the same as in frontend queue view: 5s, 10s, 30s, 1m, 5m and 10m or more.
+// Items overdue less than 5 seconds are not in queue.
+var QueueBuckets = [...]time.Duration{5 * time.Second, 10 * time.Second, 30 * time.Second, time.Minute, 5 * time.Minute, 10 * time.Minute}
+
+// Item overdue for collection.
+type QueueItem struct {
+	ItemId    string
+	HostId    string
+	Name      string
+	Key       string
+	Type      ItemType
+	NextCheck time.Time     // expected time of collection
+	Overdue   time.Duration // time passed since NextCheck
+}
+
+// Overdue items counted by QueueBuckets.
+type QueueSummary struct {
+	Total   [len(QueueBuckets)]int
+	ByType  map[ItemType][len(QueueBuckets)]int
+	Items   []QueueItem // only for QueueDetails mode, most overdue first
+	Skipped int         // items which can't be checked: without values or with delay given by user macro
+}
+
+// Estimates server queue of items overdue for collection.
+// Zabbix API has no method for queue (frontend gets it from server directly), so queue is built from
+// enabled items of monitored hosts: item is overdue if its last value is older than its update interval.
+// Pushed items like ZabbixTrapper, items with zero interval and items without values are not counted.
+// Flexible intervals and scheduling are not taken into account.
+func (api *API) QueueGet(mode QueueMode) (res QueueSummary, err error) {
+	var items []struct {
+		ItemId    string `json:"itemid"`
+		HostId    string `json:"hostid"`
+		Name      string `json:"name"`
+		Key       string `json:"key_"`
+		Type      string `json:"type"`
+		Delay     string `json:"delay"`
+		LastClock string `json:"lastclock"`
+	}
+	err = api.callInto("item.get", Params{
+		"output":    []string{"itemid", "hostid", "name", "key_", "type", "delay", "lastclock"},
+		"monitored": true,
+		"filter":    Params{"status": Enabled, "state": NormalState},
+	}, &items)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	res.ByType = make(map[ItemType][len(QueueBuckets)]int)
+	for _, item := range items {
+		t, e := strconv.Atoi(item.Type)
+		if e != nil || ItemType(t).isPushed() {
+			continue
+		}
+		delay, _, e := parseDelay(item.Delay)
+		if e != nil {
+			res.Skipped++
+			continue
+		}
+		if delay == 0 {
+			continue
+		}
+		lastClock, e := parseUnixTime(item.LastClock)
+		if e != nil || lastClock.IsZero() {
+			res.Skipped++
+			continue
+		}
+
+		next := lastClock.Add(time.Duration(delay) * time.Second)
+		overdue := now.Sub(next)
+		bucket := -1
+		for i, min := range QueueBuckets {
+			if overdue >= min {
+				bucket = i
+			}
+		}
+		if bucket < 0 {
+			continue
+		}
+
+		res.Total[bucket]++
+		byType := res.ByType[ItemType(t)]
+		byType[bucket]++
+		res.ByType[ItemType(t)] = byType
+		if mode == QueueDetails {
+			res.Items = append(res.Items, QueueItem{
+				ItemId: item.ItemId, HostId: item.HostId, Name: item.Name, Key: item.Key, Type: ItemType(t),
+				NextCheck: next, Overdue: overdue,
+			})
+		}
+	}
+
+	sort.SliceStable(res.Items, func(i, j int) bool { return res.Items[i].Overdue > res.Items[j].Overdue })
+	return
+}
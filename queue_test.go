@@ -0,0 +1,58 @@
+package zabbix_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	. "."
+)
+
+func TestQueueGet(t *testing.T) {
+	ago := func(d time.Duration) string {
+		return strconv.FormatInt(time.Now().Add(-d).Unix(), 10)
+	}
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		var params map[string]interface{}
+		call.decodeParams(&params, t)
+		if call.Method != "item.get" || params["monitored"] != true {
+			t.Errorf("Unexpected call %s: %#v", call.Method, params)
+		}
+		return []map[string]string{
+			{"itemid": "1", "type": "0", "delay": "60", "lastclock": ago(30 * time.Second)},                     // in time
+			{"itemid": "2", "type": "0", "delay": "60", "lastclock": ago(80 * time.Second)},                     // 20s overdue
+			{"itemid": "3", "type": "0", "delay": "1m", "lastclock": ago(3 * time.Minute)},                      // 2m overdue
+			{"itemid": "4", "type": "4", "delay": "30", "lastclock": ago(time.Hour)},                            // 59m30s overdue
+			{"itemid": "5", "type": "2", "delay": "0", "lastclock": ago(time.Hour)},                             // trapper
+			{"itemid": "6", "type": "0", "delay": "{$DELAY}", "lastclock": ago(time.Hour)},                      // macro
+			{"itemid": "7", "type": "0", "delay": "60", "lastclock": "0"},                                       // no values
+			{"itemid": "8", "type": "4", "delay": "30s;60/1-5,09:00-18:00", "lastclock": ago(45 * time.Second)}, // 15s overdue
+		}
+	})
+	defer server.Close()
+
+	summary, err := api.QueueGet(QueueDetails)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := [6]int{0, 2, 0, 1, 0, 1}; summary.Total != expected {
+		t.Errorf("Expected total %v, got %v", expected, summary.Total)
+	}
+	if summary.ByType[ZabbixAgent] != [6]int{0, 1, 0, 1, 0, 0} || summary.ByType[SNMPv2Agent] != [6]int{0, 1, 0, 0, 0, 1} {
+		t.Errorf("Unexpected buckets by type: %v", summary.ByType)
+	}
+	if summary.Skipped != 2 {
+		t.Errorf("Expected 2 skipped items, got %d", summary.Skipped)
+	}
+	if len(summary.Items) != 4 || summary.Items[0].ItemId != "4" || summary.Items[3].ItemId != "8" {
+		t.Errorf("Unexpected items: %#v", summary.Items)
+	}
+
+	summary, err = api.QueueGet(QueueOverview)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Items != nil || summary.Total[5] != 1 {
+		t.Errorf("Unexpected overview: %#v", summary)
+	}
+}
@@ -0,0 +1,87 @@
+package zabbix
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"strings"
+	"time"
+)
+
+// Retry policy of API calls, see api.RetryPolicy.
+// Calls are retried as is, so custom ShouldRetry retrying create methods after response timeout may create
+// duplicate objects if first attempt actually succeeded.
+type RetryPolicy struct {
+	MaxAttempts int           // total number of attempts including first one, 1 or less disables retries
+	Delay       time.Duration // pause before retry, multiplied by number of failed attempts
+
+	// Decides if failed attempt (starting from 1) should be retried. err is *Error for API errors,
+	// *TransportError for HTTP ones like 503, *TimeoutError or other error from http.Client for network ones.
+	// Nil means DefaultShouldRetry.
+	ShouldRetry func(err error, attempt int) bool
+}
+
+// Retries failures to connect, HTTP 5xx responses and, for read-only methods like host.get, response timeouts.
+// Response timeouts of other methods are not retried: first attempt may have changed objects already.
+// API errors and other network errors, like invalid certificate, are never retried: they will happen again.
+func DefaultShouldRetry(err error, attempt int) bool {
+	switch e := err.(type) {
+	case *Error:
+		return false
+	case *TransportError:
+		return e.StatusCode >= 500
+	case *TimeoutError:
+		return e.Dial || isReadMethod(e.Method)
+	}
+	var op *net.OpError
+	return errors.As(err, &op) && op.Op == "dial"
+}
+
+// Returns true for methods which don't change anything, like host.get or script.getscriptsbyhosts.
+func isReadMethod(method string) bool {
+	method = strings.ToLower(method)
+	if method == "apiinfo.version" {
+		return true
+	}
+	i := strings.Index(method, ".")
+	return i >= 0 && strings.HasPrefix(method[i+1:], "get")
+}
+
+// Calls callBytesOnce, retrying failed attempts according to api.RetryPolicy.
+func (api *API) callBytesRetrying(method string, params interface{}) (b []byte, err error) {
+	policy := api.RetryPolicy
+	for attempt := 1; ; attempt++ {
+		b, err = api.callBytesOnce(method, params)
+		if policy == nil || attempt >= policy.MaxAttempts {
+			return
+		}
+
+		failure := err
+		if failure == nil {
+			if failure = responseError(b); failure == nil {
+				return
+			}
+		}
+		shouldRetry := policy.ShouldRetry
+		if shouldRetry == nil {
+			shouldRetry = DefaultShouldRetry
+		}
+		if !shouldRetry(failure, attempt) {
+			return
+		}
+
+		api.printf("Retrying %s after attempt %d: %s", method, attempt, failure)
+		time.Sleep(policy.Delay * time.Duration(attempt))
+	}
+}
+
+// Returns API error from response body, or nil if there is none.
+func responseError(b []byte) error {
+	var response struct {
+		Error *Error `json:"error"`
+	}
+	if json.Unmarshal(b, &response) != nil || response.Error == nil {
+		return nil
+	}
+	return response.Error
+}
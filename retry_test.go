@@ -0,0 +1,76 @@
+package zabbix_test
+
+import (
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	. "."
+)
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	var calls int
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		calls++
+		if calls == 1 {
+			return &Error{Code: -32500, Message: "Application error.", Data: "Database is busy, try again later."}
+		}
+		return []map[string]string{{"hostid": "10084", "host": "db1"}}
+	})
+	defer server.Close()
+
+	var attempts []int
+	api.RetryPolicy = &RetryPolicy{
+		MaxAttempts: 3,
+		Delay:       time.Millisecond,
+		ShouldRetry: func(err error, attempt int) bool {
+			attempts = append(attempts, attempt)
+			e, ok := err.(*Error)
+			return ok && e.Code == -32500 && strings.Contains(e.Data, "busy")
+		},
+	}
+
+	hosts, err := api.HostsGet(Params{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hosts) != 1 || calls != 2 || len(attempts) != 1 || attempts[0] != 1 {
+		t.Errorf("Unexpected result: %#v, %d calls, attempts %v", hosts, calls, attempts)
+	}
+
+	// API errors are not retried by default
+	calls = 0
+	api.RetryPolicy.ShouldRetry = nil
+	_, err = api.HostsGet(Params{})
+	if _, ok := err.(*Error); !ok || calls != 1 {
+		t.Errorf("Expected API error after one call, got %#v after %d", err, calls)
+	}
+}
+
+func TestDefaultShouldRetry(t *testing.T) {
+	dial := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connect: connection refused")}
+	for i, c := range []struct {
+		err      error
+		expected bool
+	}{
+		{&Error{Code: -32500, Message: "Application error."}, false},
+		{&TransportError{StatusCode: 503}, true},
+		{&TransportError{StatusCode: 404}, false},
+		{&TimeoutError{Method: "host.get"}, true},
+		{&TimeoutError{Method: "apiinfo.version"}, true},
+		{&TimeoutError{Method: "script.getscriptsbyhosts"}, true},
+		{&TimeoutError{Method: "item.create"}, false},
+		{&TimeoutError{Method: "item.create", Dial: true}, true},
+		{&url.Error{Op: "Post", URL: "http://zabbix/api_jsonrpc.php", Err: dial}, true},
+		{&url.Error{Op: "Post", URL: "https://zabbix/api_jsonrpc.php", Err: x509.UnknownAuthorityError{}}, false},
+		{&url.Error{Op: "Post", URL: "ftp://zabbix/api_jsonrpc.php", Err: errors.New(`unsupported protocol scheme "ftp"`)}, false},
+	} {
+		if actual := DefaultShouldRetry(c.err, 1); actual != c.expected {
+			t.Errorf("%d: %v: expected %v, got %v", i, c.err, c.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package zabbix
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type (
+	ScriptType      int
+	ScriptExecuteOn int
+)
+
+const (
+	CustomScript  ScriptType = 0
+	IPMIScript    ScriptType = 1
+	SSHScript     ScriptType = 2 // Zabbix 5.4+
+	TelnetScript  ScriptType = 3 // Zabbix 5.4+
+	WebhookScript ScriptType = 5 // Zabbix 5.4+
+
+	ExecuteOnAgent  ScriptExecuteOn = 0
+	ExecuteOnServer ScriptExecuteOn = 1
+	ExecuteOnProxy  ScriptExecuteOn = 2 // Zabbix 4.0+
+)
+
+// Global script, named so to not clash with Script item type.
+// https://www.zabbix.com/documentation/5.4/manual/api/reference/script/object
+type GlobalScript struct {
+	ScriptId     string          `json:"scriptid,omitempty"`
+	Name         string          `json:"name"`
+	Type         ScriptType      `json:"type,string"`
+	Command      string          `json:"command"`
+	ExecuteOn    ScriptExecuteOn `json:"execute_on,string"`
+	Description  string          `json:"description,omitempty"`
+	Confirmation string          `json:"confirmation,omitempty"`
+	UserGroupId  string          `json:"usrgrpid,omitempty"`
+	GroupId      string          `json:"groupid,omitempty"`
+}
+
+type Scripts []GlobalScript
+
+// Wrapper for script.getscriptsbyhosts: https://www.zabbix.com/documentation/5.4/manual/api/reference/script/getscriptsbyhosts
+// Returns scripts which current user can run on host. Result depends on user: scripts are limited by
+// their user group and host group, and by user's permissions on host, so different users get different scripts.
+func (api *API) ScriptsGetByHost(hostId string) (res Scripts, err error) {
+	return api.scriptsBy("script.getscriptsbyhosts", hostId)
+}
+
+// Wrapper for script.getscriptsbyevents: https://www.zabbix.com/documentation/5.4/manual/api/reference/script/getscriptsbyevents
+// Returns scripts which current user can run on event (Zabbix 5.4+). Like ScriptsGetByHost(), result depends on user.
+func (api *API) ScriptsGetByEvent(eventId string) (res Scripts, err error) {
+	v, err := api.serverVersion()
+	if err != nil {
+		return
+	}
+	if !v.atLeast(5, 4) {
+		err = fmt.Errorf("script.getscriptsbyevents is not supported by Zabbix %s, 5.4 or later is required.", v)
+		return
+	}
+
+	return api.scriptsBy("script.getscriptsbyevents", eventId)
+}
+
+// Calls method returning scripts mapped by Id of host or event, and returns scripts for id.
+// Result without any scripts is empty array instead of object.
+func (api *API) scriptsBy(method, id string) (res Scripts, err error) {
+	var result json.RawMessage
+	err = api.callInto(method, []string{id}, &result)
+	if err != nil || isEmptyArray(result) {
+		return
+	}
+
+	var scripts map[string]Scripts
+	err = json.Unmarshal(result, &scripts)
+	res = scripts[id]
+	return
+}
@@ -0,0 +1,46 @@
+package zabbix_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "."
+)
+
+func TestScriptsGetByHost(t *testing.T) {
+	var result interface{} = map[string]interface{}{
+		"10084": []map[string]string{
+			{"scriptid": "1", "name": "Ping", "type": "0", "command": "/bin/ping -c 3 {HOST.CONN}", "execute_on": "1", "hostid": "10084"},
+			{"scriptid": "3", "name": "Detect OS", "type": "0", "command": "sudo /usr/bin/nmap -O {HOST.CONN}", "execute_on": "1",
+				"usrgrpid": "7", "groupid": "0", "confirmation": "Scan?", "hostid": "10084"},
+		},
+	}
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		var params []string
+		call.decodeParams(&params, t)
+		if call.Method != "script.getscriptsbyhosts" || !reflect.DeepEqual(params, []string{"10084"}) {
+			t.Errorf("Unexpected call %s: %#v", call.Method, params)
+		}
+		return result
+	})
+	defer server.Close()
+
+	scripts, err := api.ScriptsGetByHost("10084")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Scripts{
+		{ScriptId: "1", Name: "Ping", Type: CustomScript, Command: "/bin/ping -c 3 {HOST.CONN}", ExecuteOn: ExecuteOnServer},
+		{ScriptId: "3", Name: "Detect OS", Type: CustomScript, Command: "sudo /usr/bin/nmap -O {HOST.CONN}", ExecuteOn: ExecuteOnServer,
+			UserGroupId: "7", GroupId: "0", Confirmation: "Scan?"},
+	}
+	if !reflect.DeepEqual(scripts, expected) {
+		t.Errorf("Unexpected scripts: %#v", scripts)
+	}
+
+	result = []interface{}{}
+	scripts, err = api.ScriptsGetByHost("10084")
+	if err != nil || len(scripts) != 0 {
+		t.Errorf("Unexpected result: %#v %v", scripts, err)
+	}
+}
@@ -0,0 +1,79 @@
+package zabbix
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type (
+	ServiceAlgorithm int
+)
+
+const (
+	ServiceStatusDoNotCalculate ServiceAlgorithm = 0
+	ServiceProblemIfOneChild    ServiceAlgorithm = 1
+	ServiceProblemIfAllChildren ServiceAlgorithm = 2
+)
+
+// IT service: https://www.zabbix.com/documentation/4.0/manual/api/reference/service/object
+// IT services were reworked in Zabbix 6.0: ShowSLA and GoodSLA were removed in favor of separate SLA objects.
+type Service struct {
+	ServiceId string           `json:"serviceid,omitempty"`
+	Name      string           `json:"name"`
+	Status    int              `json:"status,string"` // severity of service problem, 0 or -1 if OK
+	Algorithm ServiceAlgorithm `json:"algorithm,string"`
+	SortOrder int              `json:"sortorder,string"`
+
+	// Fields below are supported only before Zabbix 6.0.
+	ShowSLA int    `json:"showsla,string,omitempty"`
+	GoodSLA string `json:"goodsla,omitempty"` // like "99.9000"
+}
+
+type Services []Service
+
+// SLA of service for one time interval.
+type SLAInterval struct {
+	From         int64   `json:"from"`
+	To           int64   `json:"to"`
+	SLA          float64 `json:"sla"`
+	OKTime       int64   `json:"okTime"`
+	ProblemTime  int64   `json:"problemTime"`
+	DowntimeTime int64   `json:"downtimeTime"`
+}
+
+// Result of service.getsla for one service.
+type ServiceSLA struct {
+	Status json.Number   `json:"status"` // current status of service
+	SLA    []SLAInterval `json:"sla"`
+}
+
+// Wrapper for service.get: https://www.zabbix.com/documentation/4.0/manual/api/reference/service/get
+func (api *API) ServicesGet(params Params) (res Services, err error) {
+	params = params.Clone()
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	err = api.callInto("service.get", params, &res)
+	return
+}
+
+// Wrapper for service.getsla: https://www.zabbix.com/documentation/4.0/manual/api/reference/service/getsla
+// Returns SLA of services between from and to, mapped by service Id.
+// The method was removed in Zabbix 6.0 together with old IT services, error is returned for that and later versions.
+func (api *API) ServicesGetSLA(serviceIds []string, from, to time.Time) (res map[string]ServiceSLA, err error) {
+	v, err := api.serverVersion()
+	if err != nil {
+		return
+	}
+	if v.atLeast(6, 0) {
+		err = fmt.Errorf("service.getsla is not supported by Zabbix %s, use sla.getsli instead.", v)
+		return
+	}
+
+	err = api.callInto("service.getsla", Params{
+		"serviceids": serviceIds,
+		"intervals":  []Params{{"from": from.Unix(), "to": to.Unix()}},
+	}, &res)
+	return
+}
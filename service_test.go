@@ -0,0 +1,77 @@
+package zabbix_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	. "."
+)
+
+func TestServicesGetSLA(t *testing.T) {
+	from := time.Unix(1400000000, 0)
+	to := from.Add(24 * time.Hour)
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		switch call.Method {
+		case "apiinfo.version", "APIInfo.version":
+			return "4.0.20"
+		case "service.get":
+			return []map[string]string{{
+				"serviceid": "2", "name": "Web shop", "status": "0", "algorithm": "1", "sortorder": "0",
+				"showsla": "1", "goodsla": "99.9000",
+			}}
+		case "service.getsla":
+			var params struct {
+				ServiceIds []string `json:"serviceids"`
+				Intervals  []struct {
+					From int64 `json:"from"`
+					To   int64 `json:"to"`
+				} `json:"intervals"`
+			}
+			call.decodeParams(&params, t)
+			if len(params.ServiceIds) != 1 || len(params.Intervals) != 1 || params.Intervals[0].From != from.Unix() || params.Intervals[0].To != to.Unix() {
+				t.Errorf("Unexpected params: %#v", params)
+			}
+			return json.RawMessage(`{"2": {"status": "0", "problems": [], "sla": [{
+				"from": 1400000000, "to": 1400086400, "sla": 99.5,
+				"okTime": 85968, "problemTime": 432, "downtimeTime": 0
+			}]}}`)
+		}
+		t.Errorf("Unexpected method %s", call.Method)
+		return nil
+	})
+	defer server.Close()
+
+	services, err := api.ServicesGet(Params{"serviceids": "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services) != 1 || services[0].ShowSLA != 1 || services[0].GoodSLA != "99.9000" || services[0].Algorithm != ServiceProblemIfOneChild {
+		t.Fatalf("Unexpected services: %#v", services)
+	}
+
+	sla, err := api.ServicesGetSLA([]string{services[0].ServiceId}, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	intervals := sla["2"].SLA
+	if len(intervals) != 1 || intervals[0].SLA != 99.5 || intervals[0].ProblemTime != 432 || sla["2"].Status != "0" {
+		t.Errorf("Unexpected SLA: %#v", sla)
+	}
+}
+
+func TestServicesGetSLANewVersion(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		if call.Method == "apiinfo.version" || call.Method == "APIInfo.version" {
+			return "6.0.0"
+		}
+		t.Errorf("Unexpected method %s", call.Method)
+		return nil
+	})
+	defer server.Close()
+
+	_, err := api.ServicesGetSLA([]string{"2"}, time.Now().Add(-time.Hour), time.Now())
+	if err == nil {
+		t.Error("Expected error for Zabbix 6.0")
+	}
+}
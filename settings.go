@@ -0,0 +1,66 @@
+package zabbix
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Global settings of Zabbix frontend and server: https://www.zabbix.com/documentation/6.0/manual/api/reference/settings/object
+// Set of settings differs between Zabbix versions, so only ones present in all versions with settings.get
+// are exposed as fields; Raw contains all returned settings, including them.
+type Settings struct {
+	// Custom names and hex colors of severities, indexed by PriorityType.
+	SeverityNames  [6]string
+	SeverityColors [6]string
+
+	// Working time like "1-5,09:00-18:00" and frontend theme like "blue-theme".
+	WorkPeriod   string
+	DefaultTheme string
+
+	// Ids of host group for discovered hosts and user group for database down alerts.
+	DiscoveryGroupId string
+	AlertUserGroupId string
+
+	Raw map[string]interface{}
+}
+
+func (s *Settings) UnmarshalJSON(b []byte) (err error) {
+	var raw map[string]interface{}
+	err = json.Unmarshal(b, &raw)
+	if err != nil {
+		return
+	}
+
+	*s = Settings{Raw: raw}
+	str := func(key string) string {
+		v, _ := raw[key].(string)
+		return v
+	}
+	for p := NotClassified; p <= Disaster; p++ {
+		s.SeverityNames[p] = str(fmt.Sprintf("severity_name_%d", p))
+		s.SeverityColors[p] = str(fmt.Sprintf("severity_color_%d", p))
+	}
+	s.WorkPeriod = str("work_period")
+	s.DefaultTheme = str("default_theme")
+	s.DiscoveryGroupId = str("discovery_groupid")
+	s.AlertUserGroupId = str("alert_usrgrpid")
+	return
+}
+
+// Wrapper for settings.get: https://www.zabbix.com/documentation/6.0/manual/api/reference/settings/get
+// The method exists since Zabbix 5.2. Earlier versions have no API for global settings
+// (usermacro.get with globalmacro returns only global macros), so error is returned for them.
+// Housekeeping settings are returned by separate housekeeping.get method.
+func (api *API) SettingsGet() (res Settings, err error) {
+	v, err := api.serverVersion()
+	if err != nil {
+		return
+	}
+	if !v.atLeast(5, 2) {
+		err = fmt.Errorf("settings.get is not supported by Zabbix %s, 5.2 or later is required.", v)
+		return
+	}
+
+	err = api.callInto("settings.get", Params{"output": "extend"}, &res)
+	return
+}
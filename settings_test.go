@@ -0,0 +1,62 @@
+package zabbix_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "."
+)
+
+func TestSettingsGet(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		switch call.Method {
+		case "apiinfo.version", "APIInfo.version":
+			return "6.0.12"
+		case "settings.get":
+			var params map[string]interface{}
+			call.decodeParams(&params, t)
+			if params["output"] != "extend" {
+				t.Errorf("Unexpected params: %#v", params)
+			}
+			return json.RawMessage(`{
+				"default_theme": "blue-theme", "work_period": "1-5,09:00-18:00", "discovery_groupid": "5", "alert_usrgrpid": "7",
+				"severity_name_0": "Not classified", "severity_name_1": "Information", "severity_name_2": "Warning",
+				"severity_name_3": "Average", "severity_name_4": "High", "severity_name_5": "Page now",
+				"severity_color_5": "E45959", "default_lang": "en_US", "login_attempts": "5"
+			}`)
+		}
+		t.Errorf("Unexpected method %s", call.Method)
+		return nil
+	})
+	defer server.Close()
+
+	settings, err := api.SettingsGet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.SeverityNames[Disaster] != "Page now" || settings.SeverityNames[NotClassified] != "Not classified" || settings.SeverityColors[Disaster] != "E45959" {
+		t.Errorf("Unexpected severities: %#v, %#v", settings.SeverityNames, settings.SeverityColors)
+	}
+	if settings.WorkPeriod != "1-5,09:00-18:00" || settings.DefaultTheme != "blue-theme" || settings.DiscoveryGroupId != "5" || settings.AlertUserGroupId != "7" {
+		t.Errorf("Unexpected settings: %#v", settings)
+	}
+	if settings.Raw["login_attempts"] != "5" || settings.Raw["default_lang"] != "en_US" {
+		t.Errorf("Unexpected raw settings: %#v", settings.Raw)
+	}
+}
+
+func TestSettingsGetOldVersion(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		if call.Method == "apiinfo.version" || call.Method == "APIInfo.version" {
+			return "5.0.30"
+		}
+		t.Errorf("Unexpected method %s", call.Method)
+		return nil
+	})
+	defer server.Close()
+
+	_, err := api.SettingsGet()
+	if err == nil {
+		t.Error("Expected error for Zabbix 5.0")
+	}
+}
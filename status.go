@@ -0,0 +1,36 @@
+package zabbix
+
+import (
+	"fmt"
+)
+
+// Status of item, trigger, host, user or action: all of them use 0 for enabled and 1 for disabled.
+type Status int
+
+const (
+	Enabled  Status = 0
+	Disabled Status = 1
+)
+
+func (s Status) String() string {
+	switch s {
+	case Enabled:
+		return "Enabled"
+	case Disabled:
+		return "Disabled"
+	default:
+		return fmt.Sprintf("Status(%d)", int(s))
+	}
+}
+
+// Returns Disabled for Enabled and vice versa. Unknown values are returned as is.
+func (s Status) Toggle() Status {
+	switch s {
+	case Enabled:
+		return Disabled
+	case Disabled:
+		return Enabled
+	default:
+		return s
+	}
+}
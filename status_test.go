@@ -0,0 +1,35 @@
+package zabbix_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "."
+)
+
+func TestStatus(t *testing.T) {
+	for s, expected := range map[Status]string{Enabled: "Enabled", Disabled: "Disabled", Status(5): "Status(5)"} {
+		if s.String() != expected {
+			t.Errorf("Expected %q, got %q", expected, s.String())
+		}
+	}
+
+	if Enabled.Toggle() != Disabled || Disabled.Toggle() != Enabled {
+		t.Error("Toggle failed")
+	}
+	if Monitored != Enabled || Unmonitored != Disabled {
+		t.Error("Host statuses don't match")
+	}
+
+	b, err := json.Marshal(Host{Status: Unmonitored})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]interface{}
+	if err = json.Unmarshal(b, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m["status"] != float64(1) {
+		t.Errorf("Expected status to be integer 1, got %#v", m["status"])
+	}
+}
@@ -0,0 +1,44 @@
+package zabbix
+
+type (
+	TagOperator int
+	EvalType    int
+)
+
+const (
+	TagContains  TagOperator = 0
+	TagEquals    TagOperator = 1
+	TagNotLike   TagOperator = 2 // Zabbix 4.4+
+	TagNotEqual  TagOperator = 3 // Zabbix 4.4+
+	TagExists    TagOperator = 4 // Zabbix 4.4+
+	TagNotExists TagOperator = 5 // Zabbix 4.4+
+
+	// Conditions for different tags are combined with And, conditions for the same tag with Or.
+	EvalAndOr EvalType = 0
+	EvalOr    EvalType = 2
+)
+
+// https://www.zabbix.com/documentation/3.2/manual/api/reference/trigger/object#trigger_tag
+type Tag struct {
+	Tag   string `json:"tag"`
+	Value string `json:"value"`
+}
+
+type Tags []Tag
+
+// Condition of tags query parameter of get methods.
+type TagFilter struct {
+	Tag      string      `json:"tag"`
+	Value    string      `json:"value"`
+	Operator TagOperator `json:"operator"`
+}
+
+type TagFilters []TagFilter
+
+// Adds tags and evaltype query parameters to copy of params.
+func (filters TagFilters) params(params Params, evalType EvalType) Params {
+	params = params.Clone()
+	params["tags"] = filters
+	params["evaltype"] = evalType
+	return params
+}
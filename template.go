@@ -0,0 +1,246 @@
+package zabbix
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/AlekSi/reflector"
+)
+
+// https://www.zabbix.com/documentation/2.0/manual/appendix/api/template/definitions
+type Template struct {
+	TemplateId string `json:"templateid,omitempty"`
+	Host       string `json:"host"`
+	Name       string `json:"name,omitempty"`
+
+	// Fields below used only when creating templates
+	GroupIds HostGroupIds `json:"groups,omitempty"`
+}
+
+type Templates []Template
+
+type TemplateId struct {
+	TemplateId string `json:"templateid"`
+}
+
+type TemplateIds []TemplateId
+
+// Wrapper for template.get: https://www.zabbix.com/documentation/2.0/manual/appendix/api/template/get
+func (api *API) TemplatesGet(params Params) (res Templates, err error) {
+	params = params.Clone()
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("template.get", params)
+	if err != nil {
+		return
+	}
+
+	reflector.MapsToStructs2(response.Result.([]interface{}), &res, reflector.Strconv, "json")
+	return
+}
+
+// Gets template by Id only if there is exactly 1 matching template.
+func (api *API) TemplateGetById(id string) (res *Template, err error) {
+	templates, err := api.TemplatesGet(Params{"templateids": id})
+	if err != nil {
+		return
+	}
+
+	if len(templates) == 1 {
+		res = &templates[0]
+	} else {
+		e := ExpectedOneResult(len(templates))
+		err = &e
+	}
+	return
+}
+
+// Gets template by Host only if there is exactly 1 matching template.
+func (api *API) TemplateGetByHost(host string) (res *Template, err error) {
+	templates, err := api.TemplatesGet(Params{"filter": map[string]string{"host": host}})
+	if err != nil {
+		return
+	}
+
+	if len(templates) == 1 {
+		res = &templates[0]
+	} else {
+		e := ExpectedOneResult(len(templates))
+		err = &e
+	}
+	return
+}
+
+// Wrapper for template.create: https://www.zabbix.com/documentation/2.0/manual/appendix/api/template/create
+func (api *API) TemplatesCreate(templates Templates) (err error) {
+	response, err := api.CallWithError("template.create", templates)
+	if err != nil {
+		return
+	}
+
+	templateids, err := response.ResultIDs("templateids")
+	if err != nil {
+		return
+	}
+	for i, id := range templateids {
+		templates[i].TemplateId = id
+	}
+	return
+}
+
+// Wrapper for template.delete: https://www.zabbix.com/documentation/2.0/manual/appendix/api/template/delete
+// Cleans TemplateId in all templates elements if call succeed.
+func (api *API) TemplatesDelete(templates Templates) (err error) {
+	ids := make([]string, len(templates))
+	for i, template := range templates {
+		ids[i] = template.TemplateId
+	}
+
+	err = api.TemplatesDeleteByIds(ids)
+	if err == nil {
+		for i := range templates {
+			templates[i].TemplateId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for template.delete: https://www.zabbix.com/documentation/2.0/manual/appendix/api/template/delete
+func (api *API) TemplatesDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("template.delete", ids)
+	if err != nil {
+		return
+	}
+
+	templateids, err := response.ResultIDs("templateids")
+	if err != nil {
+		return
+	}
+	if len(ids) != len(templateids) {
+		err = &ExpectedMore{len(ids), len(templateids)}
+	}
+	return
+}
+
+// Clones template: exports source template, renames it and its references in exported data
+// (item and graph hosts, trigger expressions) to newName and imports result as a new template.
+// Both technical and visible names of new template are newName.
+// Cloned items, triggers and other entities are created anew, so they are not linked to source template.
+// Source template is not changed. Fails if template with newName already exists.
+// Import rules depend on Zabbix version: for example, template groups are used since 6.2.
+func (api *API) TemplatesClone(sourceTemplateId, newName string) (res *Template, err error) {
+	source, err := api.TemplateGetById(sourceTemplateId)
+	if err != nil {
+		return
+	}
+	existing, err := api.TemplatesGet(Params{"filter": map[string]string{"host": newName}})
+	if err != nil {
+		return
+	}
+	if len(existing) != 0 {
+		err = fmt.Errorf("Template %s already exists.", newName)
+		return
+	}
+
+	response, err := api.CallWithError("configuration.export", Params{
+		"format":  "json",
+		"options": Params{"templates": []string{sourceTemplateId}},
+	})
+	if err != nil {
+		return
+	}
+
+	var exported interface{}
+	err = json.Unmarshal([]byte(response.Result.(string)), &exported)
+	if err != nil {
+		return
+	}
+	b, err := json.Marshal(renameExported(exported, source.Host, newName))
+	if err != nil {
+		return
+	}
+
+	v, err := api.serverVersion()
+	if err != nil {
+		return
+	}
+	create := Params{"createMissing": true}
+	rules := Params{
+		"templates":       create,
+		"templateLinkage": create,
+		"items":           create,
+		"discoveryRules":  create,
+		"triggers":        create,
+		"graphs":          create,
+	}
+	if v.atLeast(5, 4) {
+		rules["templateDashboards"] = create
+	} else {
+		rules["applications"] = create
+		rules["templateScreens"] = create
+	}
+	if v.atLeast(6, 2) {
+		rules["template_groups"] = create
+	} else {
+		rules["groups"] = create
+	}
+
+	_, err = api.CallWithError("configuration.import", Params{"format": "json", "source": string(b), "rules": rules})
+	if err != nil {
+		return
+	}
+
+	return api.TemplateGetByHost(newName)
+}
+
+// Replaces references to template oldHost with newHost in exported configuration: names, old {host:key.func()}
+// and new func(/host/key) (Zabbix 5.4+) syntax of expressions. Visible name of template itself is replaced whatever
+// it was. Exported uuids (Zabbix 5.4+) are regenerated, otherwise import matches source template and its entities
+// by them. Uuids of groups are kept, so existing groups are reused.
+func renameExported(v interface{}, oldHost, newHost string) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		// exported template object itself: visible name must be unique too
+		renamed := v["template"] == oldHost
+		for key, value := range v {
+			s, ok := value.(string)
+			if renamed && key == "name" {
+				v[key] = newHost
+				continue
+			}
+			switch {
+			case ok && s == oldHost && (key == "template" || key == "host" || key == "name"):
+				v[key] = newHost
+			case ok && strings.Contains(key, "expression"):
+				s = strings.Replace(s, "{"+oldHost+":", "{"+newHost+":", -1)
+				v[key] = strings.Replace(s, "/"+oldHost+"/", "/"+newHost+"/", -1)
+			case ok && key == "uuid":
+				v[key] = newUUID()
+			case key == "groups" || key == "template_groups" || key == "host_groups":
+				// keep as is
+			default:
+				v[key] = renameExported(value, oldHost, newHost)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = renameExported(value, oldHost, newHost)
+		}
+	}
+	return v
+}
+
+// Returns random version 4 UUID in format used by Zabbix: 32 hex digits without dashes.
+func newUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return hex.EncodeToString(b[:])
+}
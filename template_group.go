@@ -0,0 +1,112 @@
+package zabbix
+
+import (
+	"github.com/AlekSi/reflector"
+)
+
+// https://www.zabbix.com/documentation/6.2/manual/api/reference/templategroup/object
+type TemplateGroup struct {
+	GroupId string `json:"groupid,omitempty"`
+	Name    string `json:"name"`
+}
+
+type TemplateGroups []TemplateGroup
+
+// Returns method name for template groups: they were split from host groups in Zabbix 6.2,
+// older versions use host groups for templates.
+func (api *API) templateGroupMethod(method string) (res string, err error) {
+	v, err := api.serverVersion()
+	if err != nil {
+		return
+	}
+	if v.atLeast(6, 2) {
+		res = "templategroup." + method
+	} else {
+		res = "hostgroup." + method
+	}
+	return
+}
+
+// Wrapper for templategroup.get: https://www.zabbix.com/documentation/6.2/manual/api/reference/templategroup/get
+// Calls hostgroup.get before Zabbix 6.2.
+func (api *API) TemplateGroupsGet(params Params) (res TemplateGroups, err error) {
+	method, err := api.templateGroupMethod("get")
+	if err != nil {
+		return
+	}
+
+	params = params.Clone()
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError(method, params)
+	if err != nil {
+		return
+	}
+
+	reflector.MapsToStructs2(response.Result.([]interface{}), &res, reflector.Strconv, "json")
+	return
+}
+
+// Wrapper for templategroup.create: https://www.zabbix.com/documentation/6.2/manual/api/reference/templategroup/create
+// Calls hostgroup.create before Zabbix 6.2. Resulting Ids may be used in Template.GroupIds for any version.
+func (api *API) TemplateGroupsCreate(templateGroups TemplateGroups) (err error) {
+	method, err := api.templateGroupMethod("create")
+	if err != nil {
+		return
+	}
+
+	response, err := api.CallWithError(method, templateGroups)
+	if err != nil {
+		return
+	}
+
+	groupids, err := response.ResultIDs("groupids")
+	if err != nil {
+		return
+	}
+	for i, id := range groupids {
+		templateGroups[i].GroupId = id
+	}
+	return
+}
+
+// Wrapper for templategroup.delete: https://www.zabbix.com/documentation/6.2/manual/api/reference/templategroup/delete
+// Cleans GroupId in all templateGroups elements if call succeed.
+func (api *API) TemplateGroupsDelete(templateGroups TemplateGroups) (err error) {
+	ids := make([]string, len(templateGroups))
+	for i, group := range templateGroups {
+		ids[i] = group.GroupId
+	}
+
+	err = api.TemplateGroupsDeleteByIds(ids)
+	if err == nil {
+		for i := range templateGroups {
+			templateGroups[i].GroupId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for templategroup.delete: https://www.zabbix.com/documentation/6.2/manual/api/reference/templategroup/delete
+// Calls hostgroup.delete before Zabbix 6.2.
+func (api *API) TemplateGroupsDeleteByIds(ids []string) (err error) {
+	method, err := api.templateGroupMethod("delete")
+	if err != nil {
+		return
+	}
+
+	response, err := api.CallWithError(method, ids)
+	if err != nil {
+		return
+	}
+
+	groupids, err := response.ResultIDs("groupids")
+	if err != nil {
+		return
+	}
+	if len(ids) != len(groupids) {
+		err = &ExpectedMore{len(ids), len(groupids)}
+	}
+	return
+}
@@ -0,0 +1,56 @@
+package zabbix_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "."
+)
+
+func TestTemplateGroupsMethods(t *testing.T) {
+	for version, prefix := range map[string]string{"6.0.10": "hostgroup.", "6.2.0": "templategroup."} {
+		var methods []string
+		api, server := newMockAPI(t, func(call *mockCall) interface{} {
+			switch call.Method {
+			case "apiinfo.version", "APIInfo.version":
+				return version
+			case prefix + "create", prefix + "delete":
+				methods = append(methods, call.Method)
+				return map[string]interface{}{"groupids": []string{"42"}}
+			case prefix + "get":
+				methods = append(methods, call.Method)
+				return []map[string]string{{"groupid": "42", "name": "Templates/Team"}}
+			}
+			t.Errorf("%s: unexpected method %s", version, call.Method)
+			return nil
+		})
+
+		groups := TemplateGroups{{Name: "Templates/Team"}}
+		err := api.TemplateGroupsCreate(groups)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if groups[0].GroupId != "42" {
+			t.Errorf("%s: Id is not set: %#v", version, groups)
+		}
+
+		groups2, err := api.TemplateGroupsGet(Params{"groupids": "42"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(groups, groups2) {
+			t.Errorf("%s: groups are not equal:\n%#v\n%#v", version, groups, groups2)
+		}
+
+		err = api.TemplateGroupsDelete(groups)
+		server.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []string{prefix + "create", prefix + "get", prefix + "delete"}
+		if !reflect.DeepEqual(methods, expected) {
+			t.Errorf("%s: expected %v, got %v", version, expected, methods)
+		}
+	}
+}
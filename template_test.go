@@ -0,0 +1,186 @@
+package zabbix_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"regexp"
+	"testing"
+
+	. "."
+)
+
+func CreateTemplate(group *HostGroup, t *testing.T) *Template {
+	name := fmt.Sprintf("Template %s-%d", getHost(), rand.Int())
+	templates := Templates{{Host: name, Name: name, GroupIds: HostGroupIds{{group.GroupId}}}}
+	err := getAPI(t).TemplatesCreate(templates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &templates[0]
+}
+
+func DeleteTemplate(template *Template, t *testing.T) {
+	err := getAPI(t).TemplatesDelete(Templates{*template})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	api := getAPI(t)
+
+	group := CreateHostGroup(t)
+	defer DeleteHostGroup(group, t)
+
+	template := CreateTemplate(group, t)
+	if template.TemplateId == "" {
+		t.Errorf("Id is empty: %#v", template)
+	}
+	template.GroupIds = nil
+
+	template2, err := api.TemplateGetById(template.TemplateId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(template, template2) {
+		t.Errorf("Templates are not equal:\n%#v\n%#v", template, template2)
+	}
+
+	DeleteTemplate(template, t)
+}
+
+func TestTemplatesClone(t *testing.T) {
+	api := getAPI(t)
+
+	group := CreateHostGroup(t)
+	defer DeleteHostGroup(group, t)
+
+	source := CreateTemplate(group, t)
+	defer DeleteTemplate(source, t)
+	source.GroupIds = nil
+
+	items := Items{{HostId: source.TemplateId, Key: "key.clone", Name: "name for key", Type: ZabbixTrapper, ValueType: "0"}}
+	err := api.ItemsCreate(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clone, err := api.TemplatesClone(source.TemplateId, source.Host+" clone")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer DeleteTemplate(clone, t)
+
+	if clone.TemplateId == "" || clone.TemplateId == source.TemplateId {
+		t.Errorf("Bad clone Id: %#v", clone)
+	}
+	if clone.Host != source.Host+" clone" {
+		t.Errorf("Bad clone name: %#v", clone)
+	}
+
+	source2, err := api.TemplateGetById(source.TemplateId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(source, source2) {
+		t.Errorf("Source template changed:\n%#v\n%#v", source, source2)
+	}
+
+	items, err = api.ItemsGet(Params{"templateids": clone.TemplateId, "output": []string{"itemid", "key_", "templateid"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Key != "key.clone" || items[0].TemplateId != "0" {
+		t.Errorf("Bad cloned items: %#v", items)
+	}
+}
+
+func TestTemplatesCloneNewSyntax(t *testing.T) {
+	const export = `{"zabbix_export": {
+		"version": "6.0",
+		"groups": [{"uuid": "7df96b18c230490a9a0a9e2307226338", "name": "Templates"}],
+		"templates": [{
+			"uuid": "e2307226338490a9a0a97df96b18c230",
+			"template": "Template DB", "name": "DB server template",
+			"groups": [{"name": "Templates"}],
+			"items": [{
+				"uuid": "a9a0a97df96b18c230e2307226338490", "name": "Ping", "key": "db.ping",
+				"triggers": [{"uuid": "96b18c230e2307226338490a9a0a97df", "expression": "last(/Template DB/db.ping)=0", "name": "DB is down"}]
+			}]
+		}],
+		"triggers": [{
+			"uuid": "18c230e2307226338490a9a0a97df96b",
+			"expression": "last(/Template DB/db.ping)=0 and nodata(/Template DB/db.ping,5m)=1",
+			"recovery_expression": "last(/Template DB/db.ping)=1",
+			"name": "DB is down for long"
+		}]
+	}}`
+
+	var imported map[string]interface{}
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		var params map[string]interface{}
+		call.decodeParams(&params, t)
+		switch call.Method {
+		case "apiinfo.version", "APIInfo.version":
+			return "6.0.0"
+		case "template.get":
+			if params["templateids"] == "10001" {
+				return []map[string]string{{"templateid": "10001", "host": "Template DB", "name": "DB server template"}}
+			}
+			if imported == nil {
+				return []map[string]string{}
+			}
+			return []map[string]string{{"templateid": "10002", "host": "Template DB clone", "name": "Template DB clone"}}
+		case "configuration.export":
+			return export
+		case "configuration.import":
+			if err := json.Unmarshal([]byte(params["source"].(string)), &imported); err != nil {
+				t.Fatal(err)
+			}
+			return true
+		}
+		t.Errorf("Unexpected call %s: %#v", call.Method, params)
+		return nil
+	})
+	defer server.Close()
+
+	clone, err := api.TemplatesClone("10001", "Template DB clone")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clone.TemplateId != "10002" {
+		t.Errorf("Unexpected clone: %#v", clone)
+	}
+
+	root := imported["zabbix_export"].(map[string]interface{})
+	template := root["templates"].([]interface{})[0].(map[string]interface{})
+	item := template["items"].([]interface{})[0].(map[string]interface{})
+	itemTrigger := item["triggers"].([]interface{})[0].(map[string]interface{})
+	trigger := root["triggers"].([]interface{})[0].(map[string]interface{})
+	group := root["groups"].([]interface{})[0].(map[string]interface{})
+
+	if template["template"] != "Template DB clone" || template["name"] != "Template DB clone" || itemTrigger["expression"] != "last(/Template DB clone/db.ping)=0" {
+		t.Errorf("Unexpected template: %#v", template)
+	}
+	if trigger["expression"] != "last(/Template DB clone/db.ping)=0 and nodata(/Template DB clone/db.ping,5m)=1" ||
+		trigger["recovery_expression"] != "last(/Template DB clone/db.ping)=1" {
+		t.Errorf("Unexpected trigger: %#v", trigger)
+	}
+
+	uuid := regexp.MustCompile(`^[0-9a-f]{32}$`)
+	for old, obj := range map[string]map[string]interface{}{
+		"e2307226338490a9a0a97df96b18c230": template,
+		"a9a0a97df96b18c230e2307226338490": item,
+		"96b18c230e2307226338490a9a0a97df": itemTrigger,
+		"18c230e2307226338490a9a0a97df96b": trigger,
+	} {
+		if s, _ := obj["uuid"].(string); s == old || !uuid.MatchString(s) {
+			t.Errorf("Uuid %s is not regenerated: %#v", old, obj)
+		}
+	}
+	if group["uuid"] != "7df96b18c230490a9a0a9e2307226338" {
+		t.Errorf("Group uuid is changed: %#v", group)
+	}
+}
@@ -0,0 +1,73 @@
+package zabbix
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// https://www.zabbix.com/documentation/5.4/manual/api/reference/trend/object
+// Values are kept as strings, like in HistoryRecord.
+type Trend struct {
+	ItemId   string `json:"itemid"`
+	Clock    int64  `json:"clock,string"`
+	Num      int    `json:"num,string"`
+	ValueMin string `json:"value_min"`
+	ValueAvg string `json:"value_avg"`
+	ValueMax string `json:"value_max"`
+}
+
+type Trends []Trend
+
+// Returns start of hour of trend.
+func (t Trend) Time() time.Time {
+	return time.Unix(t.Clock, 0)
+}
+
+// Wrapper for trend.get: https://www.zabbix.com/documentation/5.4/manual/api/reference/trend/get
+// Trends are stored in separate tables for Float (trends) and Unsigned (trends_uint) items, and trend.get
+// reads table of item's value type. So all items in itemids parameter are checked to have given value type first:
+// items of other type or mixed types would silently give wrong or no data.
+func (api *API) TrendsGet(valueType ValueType, params Params) (res Trends, err error) {
+	if valueType != Float && valueType != Unsigned {
+		err = fmt.Errorf("Trends are kept only for Float and Unsigned items, not value type %d.", valueType)
+		return
+	}
+	itemIds, present := params["itemids"]
+	if !present {
+		err = errors.New("itemids parameter is required for trends.")
+		return
+	}
+
+	items, err := api.ItemsGet(Params{"itemids": itemIds, "output": []string{"itemid", "value_type"}})
+	if err != nil {
+		return
+	}
+	types := make(map[string]bool)
+	for _, item := range items {
+		types[item.ValueType] = true
+	}
+	if len(types) > 1 {
+		err = errors.New("Items have different value types, trends of Float and Unsigned items should be requested separately.")
+		return
+	}
+	for _, item := range items {
+		if item.ValueType != strconv.Itoa(int(valueType)) {
+			err = fmt.Errorf("Item %s has value type %s, not %d.", item.ItemId, item.ValueType, valueType)
+			return
+		}
+	}
+
+	params = params.Clone()
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	err = api.callInto("trend.get", params, &res)
+	return
+}
+
+// Gets trends of Unsigned items from trends_uint table, see TrendsGet.
+func (api *API) TrendsGetUint(params Params) (res Trends, err error) {
+	return api.TrendsGet(Unsigned, params)
+}
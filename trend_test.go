@@ -0,0 +1,56 @@
+package zabbix_test
+
+import (
+	"testing"
+
+	. "."
+)
+
+func TestTrendsGetUint(t *testing.T) {
+	valueTypes := map[string]string{"23": "3", "24": "3", "25": "0"}
+	var trendCalls int
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		var params map[string]interface{}
+		call.decodeParams(&params, t)
+		switch call.Method {
+		case "item.get":
+			items := []map[string]string{}
+			for _, id := range params["itemids"].([]interface{}) {
+				items = append(items, map[string]string{"itemid": id.(string), "value_type": valueTypes[id.(string)]})
+			}
+			return items
+		case "trend.get":
+			trendCalls++
+			return []map[string]string{
+				{"itemid": "23", "clock": "1400000400", "num": "60", "value_min": "1", "value_avg": "5", "value_max": "9"},
+			}
+		}
+		t.Errorf("Unexpected method %s", call.Method)
+		return nil
+	})
+	defer server.Close()
+
+	trends, err := api.TrendsGetUint(Params{"itemids": []string{"23", "24"}, "time_from": 1400000000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trends) != 1 || trends[0].Clock != 1400000400 || trends[0].Num != 60 || trends[0].ValueMax != "9" {
+		t.Errorf("Unexpected trends: %#v", trends)
+	}
+
+	_, err = api.TrendsGetUint(Params{"itemids": []string{"25"}})
+	if err == nil {
+		t.Error("Expected error for Float item")
+	}
+	_, err = api.TrendsGet(Float, Params{"itemids": []string{"23", "25"}})
+	if err == nil {
+		t.Error("Expected error for mixed value types")
+	}
+	_, err = api.TrendsGet(Text, Params{"itemids": []string{"23"}})
+	if err == nil {
+		t.Error("Expected error for Text value type")
+	}
+	if trendCalls != 1 {
+		t.Errorf("Expected 1 trend.get call, got %d", trendCalls)
+	}
+}
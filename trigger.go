@@ -0,0 +1,393 @@
+package zabbix
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	PriorityType    int
+	RecoveryMode    int
+	ManualCloseType int
+	CorrelationMode int
+	TriggerValue    int
+)
+
+const (
+	NotClassified PriorityType = 0
+	Information   PriorityType = 1
+	Warning       PriorityType = 2
+	Average       PriorityType = 3
+	High          PriorityType = 4
+	Disaster      PriorityType = 5
+
+	ExpressionRecoveryMode         RecoveryMode = 0
+	RecoveryExpressionRecoveryMode RecoveryMode = 1
+	NoneRecoveryMode               RecoveryMode = 2
+
+	ManualCloseDisabled ManualCloseType = 0
+	ManualCloseAllowed  ManualCloseType = 1
+
+	AllProblemsCorrelation CorrelationMode = 0
+	TagCorrelation         CorrelationMode = 1
+
+	OKTrigger      TriggerValue = 0
+	ProblemTrigger TriggerValue = 1
+)
+
+// https://www.zabbix.com/documentation/2.0/manual/appendix/api/trigger/definitions
+type Trigger struct {
+	TriggerId   string       `json:"triggerid,omitempty"`
+	Description string       `json:"description"`
+	Expression  string       `json:"expression"`
+	Comments    string       `json:"comments,omitempty"`
+	Priority    PriorityType `json:"priority,string"`
+	Status      Status       `json:"status,string"`
+	Url         string       `json:"url,omitempty"`
+
+	// Read-only fields: current state and time of its last change. Not sent on create or update.
+	Value      TriggerValue `json:"-"`
+	LastChange time.Time    `json:"-"`
+
+	// Fields below are supported by Zabbix 3.2+.
+	// RecoveryExpression is used only with RecoveryExpressionRecoveryMode, CorrelationTag only with TagCorrelation.
+	RecoveryMode       RecoveryMode    `json:"recovery_mode,string,omitempty"`
+	RecoveryExpression string          `json:"recovery_expression,omitempty"`
+	ManualClose        ManualCloseType `json:"manual_close,string,omitempty"`
+	CorrelationMode    CorrelationMode `json:"correlation_mode,string,omitempty"`
+	CorrelationTag     string          `json:"correlation_tag,omitempty"`
+
+	// Problem name template with macros, like "Free disk space is less than {$DISK.MIN} on {HOST.NAME}".
+	// Description is used if empty. Supported by Zabbix 5.2+, TriggersCreate and TriggersUpdate reject it for older versions.
+	EventName string `json:"event_name,omitempty"`
+
+	// Returned by selectTags query parameter, supported by Zabbix 3.2+.
+	Tags Tags `json:"tags,omitempty"`
+
+	// Fields below returned by selectHosts and selectGroups query parameters.
+	// Trigger expression may reference several hosts, so there may be more than one.
+	Hosts  Hosts      `json:"hosts,omitempty"`
+	Groups HostGroups `json:"groups,omitempty"`
+}
+
+type Triggers []Trigger
+
+// Decodes read-only fields too.
+func (trigger *Trigger) UnmarshalJSON(b []byte) (err error) {
+	type plain Trigger
+	aux := struct {
+		*plain
+		Value      string `json:"value"`
+		LastChange string `json:"lastchange"`
+	}{plain: (*plain)(trigger)}
+	err = json.Unmarshal(b, &aux)
+	if err != nil {
+		return
+	}
+
+	if aux.Value != "" {
+		var v int
+		v, err = strconv.Atoi(aux.Value)
+		if err != nil {
+			return fmt.Errorf("Failed to parse trigger value %q.", aux.Value)
+		}
+		trigger.Value = TriggerValue(v)
+	}
+	trigger.LastChange, err = parseUnixTime(aux.LastChange)
+	return
+}
+
+// Checks that recovery expression is given only with recovery expression mode.
+func (triggers Triggers) validate() error {
+	for _, trigger := range triggers {
+		switch {
+		case trigger.RecoveryMode == RecoveryExpressionRecoveryMode && trigger.RecoveryExpression == "":
+			return fmt.Errorf("Trigger %s should have recovery expression.", trigger.Description)
+		case trigger.RecoveryMode != RecoveryExpressionRecoveryMode && trigger.RecoveryExpression != "":
+			return fmt.Errorf("Trigger %s has recovery expression, but recovery mode is %d.", trigger.Description, trigger.RecoveryMode)
+		case trigger.CorrelationMode == TagCorrelation && trigger.CorrelationTag == "":
+			return fmt.Errorf("Trigger %s should have correlation tag.", trigger.Description)
+		}
+	}
+	return nil
+}
+
+// Returns error if some triggers have EventName, but Zabbix doesn't support it.
+func (api *API) checkTriggerEventNames(triggers Triggers) error {
+	for _, trigger := range triggers {
+		if trigger.EventName == "" {
+			continue
+		}
+
+		v, err := api.serverVersion()
+		if err != nil {
+			return err
+		}
+		if !v.atLeast(5, 2) {
+			return fmt.Errorf("Trigger %s has event name, but it's not supported by Zabbix %s, 5.2 or later is required.", trigger.Description, v)
+		}
+		return nil
+	}
+	return nil
+}
+
+var (
+	// {host:key.function(params)} before Zabbix 5.4
+	oldTriggerFunction = regexp.MustCompile(`\{([^{}$#:][^{}:]*):([^{}]+?)\.\w+\([^{}]*\)\}`)
+
+	// function(/host/key,params) since Zabbix 5.4
+	newTriggerFunction = regexp.MustCompile(`\w+\(/([^/,()]+)/([^,()\[]+(?:\[[^\]]*\])?)`)
+)
+
+// Returns host:key references of trigger expressions in order of appearance, without duplicates.
+func (triggers Triggers) itemReferences() (res [][2]string) {
+	seen := make(map[[2]string]bool)
+	for _, trigger := range triggers {
+		for _, expr := range []string{trigger.Expression, trigger.RecoveryExpression} {
+			matches := oldTriggerFunction.FindAllStringSubmatch(expr, -1)
+			matches = append(matches, newTriggerFunction.FindAllStringSubmatch(expr, -1)...)
+			for _, m := range matches {
+				ref := [2]string{m[1], m[2]}
+				if !seen[ref] {
+					seen[ref] = true
+					res = append(res, ref)
+				}
+			}
+		}
+	}
+	return
+}
+
+// Checks that all items referenced by trigger expressions exist with one item.get call
+// filtered by referenced hosts and keys.
+func (api *API) checkTriggerItems(triggers Triggers) error {
+	refs := triggers.itemReferences()
+	if len(refs) == 0 {
+		return nil
+	}
+
+	var hosts, keys []string
+	seenHosts := make(map[string]bool, len(refs))
+	seenKeys := make(map[string]bool, len(refs))
+	for _, ref := range refs {
+		if !seenHosts[ref[0]] {
+			seenHosts[ref[0]] = true
+			hosts = append(hosts, ref[0])
+		}
+		if !seenKeys[ref[1]] {
+			seenKeys[ref[1]] = true
+			keys = append(keys, ref[1])
+		}
+	}
+	var items []struct {
+		Key   string `json:"key_"`
+		Hosts []struct {
+			Host string `json:"host"`
+		} `json:"hosts"`
+	}
+	err := api.callInto("item.get", Params{
+		"output":      []string{"itemid", "key_"},
+		"selectHosts": []string{"host"},
+		"filter":      Params{"host": hosts, "key_": keys},
+		"webitems":    true,
+	}, &items)
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[[2]string]bool, len(items))
+	for _, item := range items {
+		for _, host := range item.Hosts {
+			existing[[2]string{host.Host, item.Key}] = true
+		}
+	}
+	var unknown []string
+	for _, ref := range refs {
+		if !existing[ref] {
+			unknown = append(unknown, ref[0]+":"+ref[1])
+		}
+	}
+	if len(unknown) != 0 {
+		return fmt.Errorf("Triggers reference unknown items: %s.", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// Wrapper for trigger.get: https://www.zabbix.com/documentation/2.0/manual/appendix/api/trigger/get
+func (api *API) TriggersGet(params Params) (res Triggers, err error) {
+	params = params.Clone()
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	err = api.callInto("trigger.get", params, &res)
+	return
+}
+
+// Gets triggers with hosts and host groups they belong to.
+// Only Id and names of hosts and groups are selected unless params say otherwise.
+func (api *API) TriggersGetWithHosts(params Params) (res Triggers, err error) {
+	params = params.Clone()
+	if _, present := params["selectHosts"]; !present {
+		params["selectHosts"] = []string{"hostid", "host", "name"}
+	}
+	if _, present := params["selectGroups"]; !present {
+		params["selectGroups"] = []string{"groupid", "name"}
+	}
+	return api.TriggersGet(params)
+}
+
+// Gets triggers with tag equal to value, with their tags selected.
+func (api *API) TriggersGetByTag(tag, value string) (res Triggers, err error) {
+	return api.TriggersGetByTags(TagFilters{{Tag: tag, Value: value, Operator: TagEquals}}, EvalAndOr)
+}
+
+// Gets triggers matching tag filters combined according to evalType, with their tags selected.
+func (api *API) TriggersGetByTags(filters TagFilters, evalType EvalType) (res Triggers, err error) {
+	return api.TriggersGet(filters.params(Params{"selectTags": "extend"}, evalType))
+}
+
+// Gets trigger by Id only if there is exactly 1 matching trigger.
+func (api *API) TriggerGetById(id string) (res *Trigger, err error) {
+	triggers, err := api.TriggersGet(Params{"triggerids": id})
+	if err != nil {
+		return
+	}
+
+	if len(triggers) == 1 {
+		res = &triggers[0]
+	} else {
+		e := ExpectedOneResult(len(triggers))
+		err = &e
+	}
+	return
+}
+
+// Wrapper for trigger.create: https://www.zabbix.com/documentation/2.0/manual/appendix/api/trigger/create
+// If api.CheckTriggerItems is set, items referenced by expressions as host and key are checked first,
+// so mistyped keys are reported before any trigger is created.
+func (api *API) TriggersCreate(triggers Triggers) (err error) {
+	err = triggers.validate()
+	if err != nil {
+		return
+	}
+	err = api.checkTriggerEventNames(triggers)
+	if err != nil {
+		return
+	}
+	if api.CheckTriggerItems {
+		err = api.checkTriggerItems(triggers)
+		if err != nil {
+			return
+		}
+	}
+
+	response, err := api.CallWithError("trigger.create", triggers)
+	if err != nil {
+		return
+	}
+
+	triggerids, err := response.ResultIDs("triggerids")
+	if err != nil {
+		return
+	}
+	for i, id := range triggerids {
+		triggers[i].TriggerId = id
+	}
+	return
+}
+
+// Like TriggersCreate, but also returns Ids of created triggers in the same order as triggers.
+func (api *API) TriggersCreateReturningIds(triggers Triggers) (ids []string, err error) {
+	err = api.TriggersCreate(triggers)
+	if err != nil {
+		return
+	}
+
+	ids = make([]string, len(triggers))
+	for i, trigger := range triggers {
+		ids[i] = trigger.TriggerId
+	}
+	return
+}
+
+// Wrapper for trigger.update: https://www.zabbix.com/documentation/2.0/manual/appendix/api/trigger/update
+func (api *API) TriggersUpdate(triggers Triggers) (err error) {
+	err = triggers.validate()
+	if err != nil {
+		return
+	}
+	err = api.checkTriggerEventNames(triggers)
+	if err != nil {
+		return
+	}
+
+	response, err := api.CallWithError("trigger.update", triggers)
+	if err != nil {
+		return
+	}
+
+	triggerids, err := response.ResultIDs("triggerids")
+	if err != nil {
+		return
+	}
+	if len(triggers) != len(triggerids) {
+		err = &ExpectedMore{len(triggers), len(triggerids)}
+	}
+	return
+}
+
+// Replaces all dependencies of trigger with triggers dependsOn. Empty dependsOn removes all dependencies.
+func (api *API) TriggersSetDependencies(triggerId string, dependsOn []string) (err error) {
+	dependencies := make([]Params, len(dependsOn))
+	for i, id := range dependsOn {
+		dependencies[i] = Params{"triggerid": id}
+	}
+	response, err := api.CallWithError("trigger.update", Params{"triggerid": triggerId, "dependencies": dependencies})
+	if err != nil {
+		return
+	}
+
+	triggerids, err := response.ResultIDs("triggerids")
+	if err == nil && len(triggerids) != 1 {
+		err = &ExpectedMore{1, len(triggerids)}
+	}
+	return
+}
+
+// Wrapper for trigger.delete: https://www.zabbix.com/documentation/2.0/manual/appendix/api/trigger/delete
+// Cleans TriggerId in all triggers elements if call succeed.
+func (api *API) TriggersDelete(triggers Triggers) (err error) {
+	ids := make([]string, len(triggers))
+	for i, trigger := range triggers {
+		ids[i] = trigger.TriggerId
+	}
+
+	err = api.TriggersDeleteByIds(ids)
+	if err == nil {
+		for i := range triggers {
+			triggers[i].TriggerId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for trigger.delete: https://www.zabbix.com/documentation/2.0/manual/appendix/api/trigger/delete
+func (api *API) TriggersDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("trigger.delete", ids)
+	if err != nil {
+		return
+	}
+
+	triggerids, err := response.ResultIDs("triggerids")
+	if err != nil {
+		return
+	}
+	if len(ids) != len(triggerids) {
+		err = &ExpectedMore{len(ids), len(triggerids)}
+	}
+	return
+}
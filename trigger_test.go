@@ -0,0 +1,387 @@
+package zabbix_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	. "."
+)
+
+func CreateTrigger(item *Item, host *Host, t *testing.T) *Trigger {
+	triggers := Triggers{{
+		Description: "trigger for " + item.Key,
+		Expression:  fmt.Sprintf("{%s:%s.last()}>0", host.Host, item.Key),
+		Priority:    Warning,
+	}}
+	err := getAPI(t).TriggersCreate(triggers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &triggers[0]
+}
+
+func DeleteTrigger(trigger *Trigger, t *testing.T) {
+	err := getAPI(t).TriggersDelete(Triggers{*trigger})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTriggers(t *testing.T) {
+	api := getAPI(t)
+
+	group := CreateHostGroup(t)
+	defer DeleteHostGroup(group, t)
+
+	host := CreateHost(group, t)
+	defer DeleteHost(host, t)
+
+	app := CreateApplication(host, t)
+	defer DeleteApplication(app, t)
+
+	item := CreateItem(app, t)
+	defer DeleteItem(item, t)
+
+	trigger := CreateTrigger(item, host, t)
+	if trigger.TriggerId == "" {
+		t.Errorf("Id is empty: %#v", trigger)
+	}
+
+	triggers, err := api.TriggersGetWithHosts(Params{"triggerids": trigger.TriggerId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(triggers) != 1 {
+		t.Fatalf("Bad triggers: %#v", triggers)
+	}
+	if len(triggers[0].Hosts) != 1 || triggers[0].Hosts[0].HostId != host.HostId {
+		t.Errorf("Bad hosts: %#v", triggers[0].Hosts)
+	}
+	if len(triggers[0].Groups) != 1 || triggers[0].Groups[0].GroupId != group.GroupId {
+		t.Errorf("Bad groups: %#v", triggers[0].Groups)
+	}
+
+	DeleteTrigger(trigger, t)
+}
+
+func TestTriggersGetWithHosts(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		var params map[string]interface{}
+		call.decodeParams(&params, t)
+		if params["selectHosts"] == nil || params["selectGroups"] == nil {
+			t.Errorf("Hosts and groups are not selected: %#v", params)
+		}
+		return []interface{}{map[string]interface{}{
+			"triggerid":   "13",
+			"description": "Free disk space is less than 20%",
+			"expression":  "{15:vfs.fs.size[/,pfree].last()}<20 | {16:vfs.fs.size[/,pfree].last()}<20",
+			"priority":    "3",
+			"status":      "0",
+			"hosts": []map[string]string{
+				{"hostid": "10084", "host": "db1", "name": "Database 1"},
+				{"hostid": "10085", "host": "db2", "name": "Database 2"},
+			},
+			"groups": []map[string]string{{"groupid": "2", "name": "Linux servers"}},
+		}}
+	})
+	defer server.Close()
+
+	triggers, err := api.TriggersGetWithHosts(Params{"triggerids": "13"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(triggers) != 1 {
+		t.Fatalf("Bad triggers: %#v", triggers)
+	}
+	trigger := triggers[0]
+	if trigger.Priority != Average || trigger.Status != Enabled {
+		t.Errorf("Bad trigger: %#v", trigger)
+	}
+	if len(trigger.Hosts) != 2 || trigger.Hosts[0].Host != "db1" || trigger.Hosts[1].HostId != "10085" {
+		t.Errorf("Bad hosts: %#v", trigger.Hosts)
+	}
+	if len(trigger.Groups) != 1 || trigger.Groups[0].Name != "Linux servers" {
+		t.Errorf("Bad groups: %#v", trigger.Groups)
+	}
+}
+
+func TestTriggersRecoveryExpression(t *testing.T) {
+	api := getAPI(t)
+
+	group := CreateHostGroup(t)
+	defer DeleteHostGroup(group, t)
+
+	host := CreateHost(group, t)
+	defer DeleteHost(host, t)
+
+	app := CreateApplication(host, t)
+	defer DeleteApplication(app, t)
+
+	item := CreateItem(app, t)
+	defer DeleteItem(item, t)
+
+	triggers := Triggers{{
+		Description:        "trigger with recovery for " + item.Key,
+		Expression:         fmt.Sprintf("{%s:%s.last()}>10", host.Host, item.Key),
+		RecoveryMode:       RecoveryExpressionRecoveryMode,
+		RecoveryExpression: fmt.Sprintf("{%s:%s.last()}<5", host.Host, item.Key),
+		ManualClose:        ManualCloseAllowed,
+	}}
+	err := api.TriggersCreate(triggers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer DeleteTrigger(&triggers[0], t)
+
+	trigger, err := api.TriggerGetById(triggers[0].TriggerId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trigger.RecoveryMode != RecoveryExpressionRecoveryMode || trigger.RecoveryExpression == "" || trigger.ManualClose != ManualCloseAllowed {
+		t.Errorf("Bad trigger: %#v", trigger)
+	}
+}
+
+func TestTriggersValidation(t *testing.T) {
+	api := getAPI(t)
+
+	err := api.TriggersCreate(Triggers{{Description: "a", Expression: "{a:b.last()}>0", RecoveryMode: RecoveryExpressionRecoveryMode}})
+	if err == nil {
+		t.Error("Expected error for missing recovery expression")
+	}
+
+	err = api.TriggersCreate(Triggers{{Description: "b", Expression: "{a:b.last()}>0", RecoveryExpression: "{a:b.last()}<0"}})
+	if err == nil {
+		t.Error("Expected error for recovery expression with expression recovery mode")
+	}
+}
+
+func TestTriggersGetByTags(t *testing.T) {
+	var expected []interface{}
+	var expectedEvalType float64
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		var params map[string]interface{}
+		call.decodeParams(&params, t)
+		if !reflect.DeepEqual(params["tags"], expected) || params["evaltype"] != expectedEvalType || params["selectTags"] != "extend" {
+			t.Errorf("Unexpected params: %#v", params)
+		}
+		return []interface{}{map[string]interface{}{
+			"triggerid": "13",
+			"priority":  "4",
+			"status":    "0",
+			"tags":      []map[string]string{{"tag": "service", "value": "db"}, {"tag": "team", "value": "ops"}},
+		}}
+	})
+	defer server.Close()
+
+	expected = []interface{}{map[string]interface{}{"tag": "service", "value": "db", "operator": float64(1)}}
+	triggers, err := api.TriggersGetByTag("service", "db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(triggers) != 1 || !reflect.DeepEqual(triggers[0].Tags, Tags{{Tag: "service", Value: "db"}, {Tag: "team", Value: "ops"}}) {
+		t.Errorf("Bad triggers: %#v", triggers)
+	}
+
+	expected = []interface{}{
+		map[string]interface{}{"tag": "service", "value": "d", "operator": float64(0)},
+		map[string]interface{}{"tag": "team", "value": "ops", "operator": float64(1)},
+	}
+	expectedEvalType = 2
+	_, err = api.TriggersGetByTags(TagFilters{
+		{Tag: "service", Value: "d", Operator: TagContains},
+		{Tag: "team", Value: "ops", Operator: TagEquals},
+	}, EvalOr)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTriggerValue(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		switch call.Method {
+		case "trigger.get":
+			return []map[string]string{{
+				"triggerid": "13", "description": "Host is down", "priority": "5", "status": "0",
+				"value": "1", "lastchange": "1400000000",
+			}}
+		case "trigger.update":
+			var triggers []map[string]interface{}
+			call.decodeParams(&triggers, t)
+			if _, present := triggers[0]["value"]; present {
+				t.Errorf("Read-only value is sent: %#v", triggers)
+			}
+			if _, present := triggers[0]["lastchange"]; present {
+				t.Errorf("Read-only lastchange is sent: %#v", triggers)
+			}
+			return map[string]interface{}{"triggerids": []string{"13"}}
+		}
+		t.Errorf("Unexpected method %s", call.Method)
+		return nil
+	})
+	defer server.Close()
+
+	trigger, err := api.TriggerGetById("13")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trigger.Value != ProblemTrigger || !trigger.LastChange.Equal(time.Unix(1400000000, 0)) || trigger.Priority != Disaster {
+		t.Errorf("Bad trigger: %#v", trigger)
+	}
+
+	err = api.TriggersUpdate(Triggers{*trigger})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTriggersSetDependencies(t *testing.T) {
+	var expected interface{}
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		var params map[string]interface{}
+		call.decodeParams(&params, t)
+		if call.Method != "trigger.update" || params["triggerid"] != "13" || !reflect.DeepEqual(params["dependencies"], expected) {
+			t.Errorf("Unexpected call %s: %#v", call.Method, params)
+		}
+		return map[string]interface{}{"triggerids": []string{"13"}}
+	})
+	defer server.Close()
+
+	expected = []interface{}{map[string]interface{}{"triggerid": "14"}, map[string]interface{}{"triggerid": "15"}}
+	err := api.TriggersSetDependencies("13", []string{"14", "15"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected = []interface{}{}
+	err = api.TriggersSetDependencies("13", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTriggersCreateCheckItems(t *testing.T) {
+	var methods []string
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		methods = append(methods, call.Method)
+		switch call.Method {
+		case "item.get":
+			var params struct {
+				Filter struct {
+					Host []string `json:"host"`
+					Key  []string `json:"key_"`
+				} `json:"filter"`
+			}
+			call.decodeParams(&params, t)
+			if expected := []string{"db1", "db2"}; !reflect.DeepEqual(params.Filter.Host, expected) {
+				t.Errorf("Unexpected hosts: %#v", params.Filter.Host)
+			}
+			expected := []string{"vfs.fs.size[/,pfree]", "agent.ping", "system.cpu.laod[all,avg1]"}
+			if len(methods) == 1 {
+				expected = expected[:2]
+			}
+			if !reflect.DeepEqual(params.Filter.Key, expected) {
+				t.Errorf("Unexpected keys: %#v", params.Filter.Key)
+			}
+			return []interface{}{
+				map[string]interface{}{"itemid": "1", "key_": "vfs.fs.size[/,pfree]", "hosts": []map[string]string{{"host": "db1"}, {"host": "db2"}}},
+				map[string]interface{}{"itemid": "2", "key_": "agent.ping", "hosts": []map[string]string{{"host": "db1"}}},
+			}
+		case "trigger.create":
+			return map[string]interface{}{"triggerids": []string{"13", "14"}}
+		}
+		t.Errorf("Unexpected method %s", call.Method)
+		return nil
+	})
+	defer server.Close()
+	api.CheckTriggerItems = true
+
+	triggers := Triggers{
+		{Description: "Low disk space", Expression: "{db1:vfs.fs.size[/,pfree].last()}<20 or {db2:vfs.fs.size[/,pfree].last()}<20"},
+		{Description: "Agent down", Expression: "nodata(/db1/agent.ping,5m)=1"},
+	}
+	err := api.TriggersCreate(triggers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	triggers = append(triggers, Trigger{Description: "High load", Expression: "{db1:system.cpu.laod[all,avg1].avg(5m)}>5 and {$LOAD:db1}=1"})
+	err = api.TriggersCreate(triggers)
+	if err == nil || !strings.Contains(err.Error(), "db1:system.cpu.laod[all,avg1]") {
+		t.Errorf("Expected error for unknown item, got %v", err)
+	}
+	if !reflect.DeepEqual(methods, []string{"item.get", "trigger.create", "item.get"}) {
+		t.Errorf("Unexpected methods: %v", methods)
+	}
+}
+
+func TestTriggersCreateReturningIds(t *testing.T) {
+	for _, result := range []interface{}{
+		[]string{"13", "14"},
+		map[string]string{"1": "14", "0": "13"},
+	} {
+		api, server := newMockAPI(t, func(call *mockCall) interface{} {
+			if call.Method != "trigger.create" {
+				t.Errorf("Unexpected method %s", call.Method)
+				return nil
+			}
+			return map[string]interface{}{"triggerids": result}
+		})
+
+		triggers := Triggers{
+			{Description: "CPU load is high", Expression: "last(/db1/system.cpu.load)>5"},
+			{Description: "Agent down", Expression: "nodata(/db1/agent.ping,5m)=1"},
+		}
+		ids, err := api.TriggersCreateReturningIds(triggers)
+		server.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(ids, []string{"13", "14"}) {
+			t.Errorf("%#v: unexpected ids %v", result, ids)
+		}
+		if triggers[0].TriggerId != "13" || triggers[1].TriggerId != "14" {
+			t.Errorf("%#v: unexpected triggers %#v", result, triggers)
+		}
+	}
+}
+
+func TestTriggersCreateEventName(t *testing.T) {
+	for version, supported := range map[string]bool{"5.0.10": false, "5.2.0": true} {
+		var created []map[string]interface{}
+		api, server := newMockAPI(t, func(call *mockCall) interface{} {
+			switch call.Method {
+			case "apiinfo.version", "APIInfo.version":
+				return version
+			case "trigger.create":
+				call.decodeParams(&created, t)
+				return map[string]interface{}{"triggerids": []string{"13"}}
+			}
+			t.Errorf("Unexpected method %s", call.Method)
+			return nil
+		})
+
+		triggers := Triggers{{
+			Description: "Low disk space",
+			Expression:  "{db1:vfs.fs.size[/,pfree].last()}<{$DISK.MIN}",
+			EventName:   "Free disk space is less than {$DISK.MIN}% on {HOST.NAME}",
+		}}
+		err := api.TriggersCreate(triggers)
+		server.Close()
+		if !supported {
+			if err == nil || created != nil {
+				t.Errorf("%s: expected error, got %v", version, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(created) != 1 || created[0]["event_name"] != "Free disk space is less than {$DISK.MIN}% on {HOST.NAME}" {
+			t.Errorf("%s: unexpected params %#v", version, created)
+		}
+	}
+}
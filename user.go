@@ -0,0 +1,81 @@
+package zabbix
+
+type (
+	UserType int
+)
+
+const (
+	ZabbixUser       UserType = 1
+	ZabbixAdmin      UserType = 2
+	ZabbixSuperAdmin UserType = 3
+)
+
+type UserGroupId struct {
+	UserGroupId string `json:"usrgrpid"`
+}
+
+type UserGroupIds []UserGroupId
+
+// https://www.zabbix.com/documentation/3.0/manual/api/reference/user/object
+type User struct {
+	UserId   string `json:"userid,omitempty"`
+	Alias    string `json:"alias,omitempty"`    // before Zabbix 5.4
+	Username string `json:"username,omitempty"` // Zabbix 5.4+
+	Name     string `json:"name,omitempty"`
+	Surname  string `json:"surname,omitempty"`
+
+	// Replaced by user roles in Zabbix 5.2.
+	Type UserType `json:"type,string,omitempty"`
+
+	// Returned by selectMedias query parameter. Use UsersCreateMedia and UsersUpdateMedia to change them.
+	Medias UserMedias `json:"medias,omitempty"`
+
+	// Fields below used only when creating and updating users. Password is never logged.
+	Password   string       `json:"passwd,omitempty"`
+	UserGroups UserGroupIds `json:"usrgrps,omitempty"`
+}
+
+type Users []User
+
+// Wrapper for user.get: https://www.zabbix.com/documentation/3.0/manual/api/reference/user/get
+func (api *API) UsersGet(params Params) (res Users, err error) {
+	params = params.Clone()
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	err = api.callInto("user.get", params, &res)
+	return
+}
+
+// Wrapper for user.update: https://www.zabbix.com/documentation/3.0/manual/api/reference/user/update
+// Zabbix refuses some changes, like removing the last super admin from its group; returned *Error describes them.
+func (api *API) UsersUpdate(users Users) (err error) {
+	response, err := api.CallWithError("user.update", users)
+	if err != nil {
+		return
+	}
+
+	userids, err := response.ResultIDs("userids")
+	if err != nil {
+		return
+	}
+	if len(users) != len(userids) {
+		err = &ExpectedMore{len(users), len(userids)}
+	}
+	return
+}
+
+// Replaces user groups of user.
+func (api *API) SetUserGroups(userId string, groupIds []string) (err error) {
+	groups := make(UserGroupIds, len(groupIds))
+	for i, id := range groupIds {
+		groups[i].UserGroupId = id
+	}
+	return api.UsersUpdate(Users{{UserId: userId, UserGroups: groups}})
+}
+
+// Sets new password of user.
+// Zabbix 6.4+ also requires current password to change password of logged in user, so use it for other users.
+func (api *API) ChangeUserPassword(userId, newPass string) (err error) {
+	return api.UsersUpdate(Users{{UserId: userId, Password: newPass}})
+}
@@ -0,0 +1,118 @@
+package zabbix
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Host or template macro: https://www.zabbix.com/documentation/3.0/manual/api/reference/usermacro/object
+type UserMacro struct {
+	HostMacroId string `json:"hostmacroid,omitempty"`
+	HostId      string `json:"hostid,omitempty"`
+	Macro       string `json:"macro"` // like "{$SNMP_COMMUNITY}"
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"` // Zabbix 4.4+
+}
+
+type UserMacros []UserMacro
+
+// Host macro which takes precedence over template macro with the same name.
+type MacroOverride struct {
+	Host     UserMacro
+	Template UserMacro
+}
+
+// Result of PreviewMacroInheritance. All lists are sorted by macro name.
+type MacroPreview struct {
+	Inherited  UserMacros      // template macros host will get, HostId is Id of template defining macro
+	Overridden []MacroOverride // template macros hidden by host ones
+	Local      UserMacros      // host macros not defined by template
+}
+
+// Previews macros of host after linking template to it, without changing anything.
+// Host macros always win. Template macros are collected with nested templates: macro defined by template
+// itself wins over the same macro of templates linked to it, and so on level by level; on the same level
+// template with smaller Id wins, like in Zabbix. Macros of templates already linked to host and global
+// macros are not considered.
+func (api *API) PreviewMacroInheritance(hostId, templateId string) (res MacroPreview, err error) {
+	hosts, err := api.HostsGetWithMacros(Params{"hostids": hostId, "output": []string{"hostid"}})
+	if err != nil {
+		return
+	}
+	if len(hosts) != 1 {
+		e := ExpectedOneResult(len(hosts))
+		err = &e
+		return
+	}
+
+	templateMacros, err := api.templateMacros(templateId)
+	if err != nil {
+		return
+	}
+
+	for _, macro := range hosts[0].Macros {
+		if tm, ok := templateMacros[macro.Macro]; ok {
+			res.Overridden = append(res.Overridden, MacroOverride{Host: macro, Template: tm})
+			delete(templateMacros, macro.Macro)
+		} else {
+			res.Local = append(res.Local, macro)
+		}
+	}
+	for _, macro := range templateMacros {
+		res.Inherited = append(res.Inherited, macro)
+	}
+
+	sort.Slice(res.Inherited, func(i, j int) bool { return res.Inherited[i].Macro < res.Inherited[j].Macro })
+	sort.Slice(res.Overridden, func(i, j int) bool { return res.Overridden[i].Host.Macro < res.Overridden[j].Host.Macro })
+	sort.Slice(res.Local, func(i, j int) bool { return res.Local[i].Macro < res.Local[j].Macro })
+	return
+}
+
+// Returns macros of template and templates linked to it, mapped by macro name. Templates are requested
+// level by level, so nearest definition of macro wins.
+func (api *API) templateMacros(templateId string) (res map[string]UserMacro, err error) {
+	res = make(map[string]UserMacro)
+	seen := map[string]bool{templateId: true}
+	ids := []string{templateId}
+	for level := 0; len(ids) != 0; level++ {
+		var templates []struct {
+			TemplateId      string      `json:"templateid"`
+			Macros          UserMacros  `json:"macros"`
+			ParentTemplates TemplateIds `json:"parentTemplates"`
+		}
+		err = api.callInto("template.get", Params{
+			"templateids": ids, "output": []string{"templateid"},
+			"selectMacros": "extend", "selectParentTemplates": []string{"templateid"},
+		}, &templates)
+		if err != nil {
+			return
+		}
+		if level == 0 && len(templates) == 0 {
+			err = fmt.Errorf("Template %s is not found.", templateId)
+			return
+		}
+
+		// compare Ids as numbers
+		sort.Slice(templates, func(i, j int) bool {
+			a, b := templates[i].TemplateId, templates[j].TemplateId
+			return len(a) < len(b) || len(a) == len(b) && a < b
+		})
+
+		ids = nil
+		for _, template := range templates {
+			for _, macro := range template.Macros {
+				if _, present := res[macro.Macro]; !present {
+					macro.HostId = template.TemplateId
+					res[macro.Macro] = macro
+				}
+			}
+			for _, parent := range template.ParentTemplates {
+				if !seen[parent.TemplateId] {
+					seen[parent.TemplateId] = true
+					ids = append(ids, parent.TemplateId)
+				}
+			}
+		}
+	}
+	return
+}
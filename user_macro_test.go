@@ -0,0 +1,77 @@
+package zabbix_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "."
+)
+
+func TestPreviewMacroInheritance(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		var params map[string]interface{}
+		call.decodeParams(&params, t)
+		switch call.Method {
+		case "host.get":
+			return []interface{}{map[string]interface{}{
+				"hostid": "10084",
+				"macros": []map[string]string{
+					{"hostmacroid": "1", "hostid": "10084", "macro": "{$MYSQL.PORT}", "value": "3307"},
+					{"hostmacroid": "2", "hostid": "10084", "macro": "{$OWNER}", "value": "dba"},
+				},
+			}}
+		case "template.get":
+			switch ids := params["templateids"].([]interface{}); {
+			case reflect.DeepEqual(ids, []interface{}{"10001"}):
+				return []interface{}{map[string]interface{}{
+					"templateid": "10001",
+					"macros": []map[string]string{
+						{"hostmacroid": "11", "hostid": "10001", "macro": "{$MYSQL.PORT}", "value": "3306"},
+						{"hostmacroid": "12", "hostid": "10001", "macro": "{$MYSQL.USER}", "value": "zbx_monitor"},
+					},
+					"parentTemplates": []map[string]string{{"templateid": "10010"}, {"templateid": "10002"}},
+				}}
+			case reflect.DeepEqual(ids, []interface{}{"10010", "10002"}):
+				return []interface{}{
+					map[string]interface{}{
+						"templateid": "10010",
+						"macros": []map[string]string{
+							{"hostmacroid": "31", "hostid": "10010", "macro": "{$TIMEOUT}", "value": "10s"},
+						},
+						"parentTemplates": []map[string]string{},
+					},
+					map[string]interface{}{
+						"templateid": "10002",
+						"macros": []map[string]string{
+							{"hostmacroid": "21", "hostid": "10002", "macro": "{$MYSQL.USER}", "value": "root"},
+							{"hostmacroid": "22", "hostid": "10002", "macro": "{$TIMEOUT}", "value": "3s"},
+						},
+						"parentTemplates": []map[string]string{{"templateid": "10001"}},
+					},
+				}
+			}
+		}
+		t.Errorf("Unexpected call %s: %#v", call.Method, params)
+		return nil
+	})
+	defer server.Close()
+
+	preview, err := api.PreviewMacroInheritance("10084", "10001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := MacroPreview{
+		Inherited: UserMacros{
+			{HostMacroId: "12", HostId: "10001", Macro: "{$MYSQL.USER}", Value: "zbx_monitor"},
+			{HostMacroId: "22", HostId: "10002", Macro: "{$TIMEOUT}", Value: "3s"},
+		},
+		Overridden: []MacroOverride{{
+			Host:     UserMacro{HostMacroId: "1", HostId: "10084", Macro: "{$MYSQL.PORT}", Value: "3307"},
+			Template: UserMacro{HostMacroId: "11", HostId: "10001", Macro: "{$MYSQL.PORT}", Value: "3306"},
+		}},
+		Local: UserMacros{{HostMacroId: "2", HostId: "10084", Macro: "{$OWNER}", Value: "dba"}},
+	}
+	if !reflect.DeepEqual(preview, expected) {
+		t.Errorf("Unexpected preview:\n%#v\n%#v", preview, expected)
+	}
+}
@@ -0,0 +1,217 @@
+package zabbix
+
+import (
+	"encoding/json"
+)
+
+type (
+	// Set of trigger severities, bit 1<<priority for each one.
+	SeverityMask int
+
+	MediaStatus int
+)
+
+const (
+	EnabledMedia  MediaStatus = 0
+	DisabledMedia MediaStatus = 1
+)
+
+// Returns mask with given severities set.
+func NewSeverityMask(priorities ...PriorityType) (mask SeverityMask) {
+	for _, p := range priorities {
+		mask |= 1 << uint(p)
+	}
+	return
+}
+
+// Returns true if mask includes given severity.
+func (mask SeverityMask) Has(p PriorityType) bool {
+	return mask&(1<<uint(p)) != 0
+}
+
+// Returns severities included in mask, from NotClassified to Disaster.
+func (mask SeverityMask) Priorities() (res []PriorityType) {
+	for p := NotClassified; p <= Disaster; p++ {
+		if mask.Has(p) {
+			res = append(res, p)
+		}
+	}
+	return
+}
+
+// Recipients of media. Zabbix 4.0+ uses array for email media, a single string is used otherwise.
+// Shape is decided by UserMedia, see its MarshalJSON.
+type MediaSendTo []string
+
+func (s *MediaSendTo) UnmarshalJSON(b []byte) (err error) {
+	if len(b) != 0 && b[0] == '[' {
+		var a []string
+		err = json.Unmarshal(b, &a)
+		*s = a
+		return
+	}
+	var str string
+	err = json.Unmarshal(b, &str)
+	*s = MediaSendTo{str}
+	return
+}
+
+// https://www.zabbix.com/documentation/3.0/manual/api/reference/usermedia/object
+type UserMedia struct {
+	MediaId     string       `json:"mediaid,omitempty"`
+	UserId      string       `json:"userid,omitempty"`
+	MediaTypeId string       `json:"mediatypeid"`
+	SendTo      MediaSendTo  `json:"sendto"`
+	Active      MediaStatus  `json:"active,string"`
+	Severity    SeverityMask `json:"severity,string"`
+	Period      string       `json:"period,omitempty"` // time period like "1-5,09:00-18:00;6-7,10:00-16:00"
+
+	// true if SendTo should be sent as array even with single recipient
+	sendToArray bool
+}
+
+// Remembers if sendto was array, to send it back in the same shape.
+func (media *UserMedia) UnmarshalJSON(b []byte) (err error) {
+	type plain UserMedia
+	aux := struct {
+		*plain
+		SendTo json.RawMessage `json:"sendto"`
+	}{plain: (*plain)(media)}
+	err = json.Unmarshal(b, &aux)
+	if err != nil || len(aux.SendTo) == 0 {
+		return
+	}
+	media.sendToArray = aux.SendTo[0] == '['
+	return json.Unmarshal(aux.SendTo, &media.SendTo)
+}
+
+// Sends single recipient as string, unless sendto was decoded from array or media is email one on Zabbix 4.0+
+// (see UsersCreateMedia). Other number of recipients is sent as array.
+func (media UserMedia) MarshalJSON() ([]byte, error) {
+	type plain UserMedia
+	var sendTo interface{} = []string(media.SendTo)
+	if len(media.SendTo) == 1 && !media.sendToArray {
+		sendTo = media.SendTo[0]
+	}
+	return json.Marshal(struct {
+		plain
+		SendTo interface{} `json:"sendto"`
+	}{plain(media), sendTo})
+}
+
+type UserMedias []UserMedia
+
+// Returns medias without read-only fields which are rejected in updates.
+func (medias UserMedias) forUpdate() UserMedias {
+	res := make(UserMedias, len(medias))
+	for i, media := range medias {
+		media.MediaId = ""
+		media.UserId = ""
+		res[i] = media
+	}
+	return res
+}
+
+// Adds medias to every given user, keeping existing ones.
+// Uses user.addmedia before Zabbix 4.0 and replaces all medias with existing and new ones since it.
+// Since Zabbix 4.0 recipients of email medias are sent as array, so media types are requested for new medias.
+func (api *API) UsersCreateMedia(userIds []string, medias UserMedias) (err error) {
+	v, err := api.serverVersion()
+	if err != nil {
+		return
+	}
+	if !v.atLeast(4, 0) {
+		_, err = api.CallWithError("user.addmedia", Params{"users": userIdParams(userIds), "medias": medias.forUpdate()})
+		return
+	}
+
+	users, err := api.UsersGet(Params{"userids": userIds, "output": []string{"userid"}, "selectMedias": "extend"})
+	if err != nil {
+		return
+	}
+	if len(users) != len(userIds) {
+		err = &ExpectedMore{len(userIds), len(users)}
+		return
+	}
+	for _, user := range users {
+		err = api.UsersUpdateMedia([]string{user.UserId}, append(user.Medias, medias...))
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Replaces medias of every given user: medias which are not given are removed.
+// Uses user.updatemedia before Zabbix 4.0, and user.update with user_medias (medias since Zabbix 5.2) since it.
+func (api *API) UsersUpdateMedia(userIds []string, medias UserMedias) (err error) {
+	v, err := api.serverVersion()
+	if err != nil {
+		return
+	}
+	medias = medias.forUpdate()
+	if !v.atLeast(4, 0) {
+		_, err = api.CallWithError("user.updatemedia", Params{"users": userIdParams(userIds), "medias": medias})
+		return
+	}
+	err = api.setSendToArrays(medias)
+	if err != nil {
+		return
+	}
+
+	field := "user_medias"
+	if v.atLeast(5, 2) {
+		field = "medias"
+	}
+	users := make([]Params, len(userIds))
+	for i, id := range userIds {
+		users[i] = Params{"userid": id, field: medias}
+	}
+	response, err := api.CallWithError("user.update", users)
+	if err != nil {
+		return
+	}
+
+	userids, err := response.ResultIDs("userids")
+	if err == nil && len(userids) != len(userIds) {
+		err = &ExpectedMore{len(userIds), len(userids)}
+	}
+	return
+}
+
+// Marks email medias with single recipient to send it as array, as required by Zabbix 4.0+.
+// Media types are requested only for medias not decoded from API.
+func (api *API) setSendToArrays(medias UserMedias) (err error) {
+	var ids []string
+	for _, media := range medias {
+		if len(media.SendTo) == 1 && !media.sendToArray {
+			ids = append(ids, media.MediaTypeId)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	mediaTypes, err := api.MediaTypesGet(Params{"mediatypeids": ids, "output": []string{"mediatypeid", "type"}})
+	if err != nil {
+		return
+	}
+	email := make(map[string]bool, len(mediaTypes))
+	for _, mediaType := range mediaTypes {
+		email[mediaType.MediaTypeId] = mediaType.Type == EmailMediaType
+	}
+	for i := range medias {
+		if email[medias[i].MediaTypeId] {
+			medias[i].sendToArray = true
+		}
+	}
+	return
+}
+
+func userIdParams(ids []string) []Params {
+	res := make([]Params, len(ids))
+	for i, id := range ids {
+		res[i] = Params{"userid": id}
+	}
+	return res
+}
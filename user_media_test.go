@@ -0,0 +1,82 @@
+package zabbix_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	. "."
+)
+
+func TestUserMedias(t *testing.T) {
+	var updates []map[string]interface{}
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		switch call.Method {
+		case "apiinfo.version", "APIInfo.version":
+			return "5.2.6"
+		case "user.get":
+			var params map[string]interface{}
+			call.decodeParams(&params, t)
+			if params["selectMedias"] != "extend" {
+				t.Errorf("Unexpected params: %#v", params)
+			}
+			return json.RawMessage(`[{"userid": "3", "medias": [{"mediaid": "1", "userid": "3", "mediatypeid": "1",
+				"sendto": ["oncall@example.com"], "active": "0", "severity": "48", "period": "1-5,09:00-18:00"}]}]`)
+		case "mediatype.get":
+			var params map[string]interface{}
+			call.decodeParams(&params, t)
+			if !reflect.DeepEqual(params["mediatypeids"], []interface{}{"3", "1"}) {
+				t.Errorf("Unexpected params: %#v", params)
+			}
+			return []map[string]string{{"mediatypeid": "1", "type": "0"}, {"mediatypeid": "3", "type": "2"}}
+		case "user.update":
+			call.decodeParams(&updates, t)
+			return map[string]interface{}{"userids": []string{"3"}}
+		}
+		t.Errorf("Unexpected method %s", call.Method)
+		return nil
+	})
+	defer server.Close()
+
+	users, err := api.UsersGet(Params{"userids": "3", "selectMedias": "extend"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 || len(users[0].Medias) != 1 {
+		t.Fatalf("Unexpected users: %#v", users)
+	}
+	media := users[0].Medias[0]
+	if !reflect.DeepEqual(media.SendTo, MediaSendTo{"oncall@example.com"}) || media.Period != "1-5,09:00-18:00" || media.Active != EnabledMedia {
+		t.Errorf("Unexpected media: %#v", media)
+	}
+	if media.Severity != NewSeverityMask(High, Disaster) || media.Severity.Has(Average) {
+		t.Errorf("Unexpected severity: %d", media.Severity)
+	}
+	if !reflect.DeepEqual(media.Severity.Priorities(), []PriorityType{High, Disaster}) {
+		t.Errorf("Unexpected priorities: %v", media.Severity.Priorities())
+	}
+
+	phone := UserMedia{MediaTypeId: "3", SendTo: MediaSendTo{"+100500"}, Severity: NewSeverityMask(Disaster), Period: "1-7,00:00-24:00"}
+	email := UserMedia{MediaTypeId: "1", SendTo: MediaSendTo{"dba@example.com"}, Severity: NewSeverityMask(Disaster)}
+	err = api.UsersCreateMedia([]string{"3"}, UserMedias{phone, email})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) != 1 || updates[0]["userid"] != "3" {
+		t.Fatalf("Unexpected updates: %#v", updates)
+	}
+	medias, _ := updates[0]["medias"].([]interface{})
+	if len(medias) != 3 {
+		t.Fatalf("Unexpected medias: %#v", updates[0])
+	}
+	first, second, third := medias[0].(map[string]interface{}), medias[1].(map[string]interface{}), medias[2].(map[string]interface{})
+	if first["mediaid"] != nil || !reflect.DeepEqual(first["sendto"], []interface{}{"oncall@example.com"}) || first["severity"] != "48" {
+		t.Errorf("Unexpected existing media: %#v", first)
+	}
+	if second["sendto"] != "+100500" || second["severity"] != "32" {
+		t.Errorf("Unexpected new media: %#v", second)
+	}
+	if !reflect.DeepEqual(third["sendto"], []interface{}{"dba@example.com"}) {
+		t.Errorf("Unexpected new email media: %#v", third)
+	}
+}
@@ -0,0 +1,67 @@
+package zabbix_test
+
+import (
+	"bytes"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "."
+)
+
+func TestSetUserGroups(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		var users []map[string]interface{}
+		call.decodeParams(&users, t)
+		expected := []map[string]interface{}{{
+			"userid":  "3",
+			"usrgrps": []interface{}{map[string]interface{}{"usrgrpid": "7"}, map[string]interface{}{"usrgrpid": "8"}},
+		}}
+		if call.Method != "user.update" || !reflect.DeepEqual(users, expected) {
+			t.Errorf("Unexpected call %s: %#v", call.Method, users)
+		}
+		return map[string]interface{}{"userids": []string{"3"}}
+	})
+	defer server.Close()
+
+	err := api.SetUserGroups("3", []string{"7", "8"})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestChangeUserPassword(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		var users []map[string]interface{}
+		call.decodeParams(&users, t)
+		expected := []map[string]interface{}{{"userid": "3", "passwd": "secret-new"}}
+		if call.Method != "user.update" || !reflect.DeepEqual(users, expected) {
+			t.Errorf("Unexpected call %s: %#v", call.Method, users)
+		}
+		return map[string]interface{}{"userids": []string{"3"}}
+	})
+	defer server.Close()
+	var buf bytes.Buffer
+	api.Logger = log.New(&buf, "", 0)
+
+	err := api.ChangeUserPassword("3", "secret-new")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "secret-") || !strings.Contains(buf.String(), "user.update") {
+		t.Errorf("Unexpected log:\n%s", buf.String())
+	}
+}
+
+func TestSetUserGroupsLastSuperAdmin(t *testing.T) {
+	api, server := newMockAPI(t, func(call *mockCall) interface{} {
+		return &Error{Code: -32500, Message: "Application error.", Data: "User cannot change own user group."}
+	})
+	defer server.Close()
+
+	err := api.SetUserGroups("1", []string{"8"})
+	if e, ok := err.(*Error); !ok || e.Data != "User cannot change own user group." {
+		t.Errorf("Expected API error, got %#v", err)
+	}
+}
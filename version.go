@@ -0,0 +1,57 @@
+package zabbix
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Zabbix version as major, minor and patch numbers.
+type version [3]int
+
+func parseVersion(s string) (v version, err error) {
+	parts := strings.SplitN(s, ".", 3)
+	for i, p := range parts {
+		// patch part may contain suffix like "0rc1"
+		end := strings.IndexFunc(p, func(r rune) bool { return r < '0' || r > '9' })
+		if end >= 0 {
+			p = p[:end]
+		}
+		v[i], err = strconv.Atoi(p)
+		if err != nil {
+			err = fmt.Errorf("Failed to parse version %q.", s)
+			return
+		}
+	}
+	return
+}
+
+// Returns true if v is major.minor or later.
+func (v version) atLeast(major, minor int) bool {
+	return v[0] > major || (v[0] == major && v[1] >= minor)
+}
+
+func (v version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v[0], v[1], v[2])
+}
+
+// Returns Zabbix version, calling Version() only once.
+func (api *API) serverVersion() (v version, err error) {
+	api.versionM.Lock()
+	defer api.versionM.Unlock()
+
+	if api.version != nil {
+		v = *api.version
+		return
+	}
+
+	s, err := api.Version()
+	if err != nil {
+		return
+	}
+	v, err = parseVersion(s)
+	if err == nil {
+		api.version = &v
+	}
+	return
+}